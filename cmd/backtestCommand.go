@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/backtest"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/estimator"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var backtestCommand = &cobra.Command{
+	Use:   "backtest",
+	Short: "Scores the estimators on a historical block range",
+	Long: `Scores the estimators on a historical block range. A simulated head is stepped
+from --from to --to, at each head every estimator predicts using only the blocks
+up to the head. The predictions are scored against the following blocks, the time
+is taken from the head, so a backtest is deterministic. Blocks are cached in
+--blocks, with --offline no node is needed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		store, err := newBlockStore(backtestOptions.blocks, backtestOptions.offline)
+		if err != nil {
+			return err
+		}
+
+		err = os.MkdirAll(backtestOptions.outputDir, 0770)
+		if err != nil {
+			return err
+		}
+
+		chain := backtest.NewChain(store)
+		subjects := make([]*backtest.Subject, len(backtestOptions.estimators))
+		for i, name := range backtestOptions.estimators {
+			subjects[i], err = newBacktestSubject(name, chain, backtestOptions.outputDir)
+			if err != nil {
+				return err
+			}
+		}
+
+		bt := backtest.NewBacktest(chain, subjects, rootOptions.horizon, logger)
+		err = bt.Run(backtestOptions.from, backtestOptions.to)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("backtest complete", zap.Any("summary", bt.Summary()))
+		return nil
+	},
+}
+
+var (
+	backtestOptions struct {
+		from       int64
+		to         int64
+		estimators []string
+		blocks     string
+		offline    bool
+		outputDir  string
+	}
+)
+
+// newBlockStore creates the store of the historical blocks
+func newBlockStore(dir string, offline bool) (*backtest.BlockStore, error) {
+	if offline {
+		return backtest.NewBlockStore(dir, backtest.ModeOffline, nil)
+	}
+
+	return backtest.NewBlockStore(dir, backtest.ModeCached, rpcClient)
+}
+
+// newBacktestSubject creates the named estimator on the chain, its scores are
+// written to outputDir
+func newBacktestSubject(name string, chain *backtest.Chain, outputDir string) (*backtest.Subject, error) {
+	options, tiers, err := estimatorOptions(name)
+	if err != nil {
+		return nil, err
+	}
+
+	scores, err := newBacktestScores(name, tiers, chain, outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := estimator.New(name, chain, append(options, estimator.WithClock(chain), estimator.WithRecorder(scores))...)
+	if err != nil {
+		scores.Close()
+		return nil, err
+	}
+	return &backtest.Subject{Name: name, Estimator: e, Scores: scores}, nil
+}
+
+// newBacktestScores creates scores which load the blocks from the chain and
+// name their files by the time of the head
+func newBacktestScores(name string, tiers []string, chain *backtest.Chain, outputDir string) (*scoring.Scores, error) {
+	config := scoreConfig(name, tiers)
+	config.OutputDir = outputDir
+	config.Clock = chain
+	return scoring.NewScores(config, chain, classifier, logger)
+}
+
+func init() {
+	RootCmd.AddCommand(backtestCommand)
+
+	backtestCommand.Flags().Int64Var(&backtestOptions.from, "from", 0, "first head of the backtest")
+	backtestCommand.Flags().Int64Var(&backtestOptions.to, "to", 0, "last head of the backtest")
+	backtestCommand.Flags().StringSliceVarP(&backtestOptions.estimators, "estimators", "e", estimator.Names, "estimators to backtest")
+	backtestCommand.Flags().StringVar(&backtestOptions.blocks, "blocks", "./output/blocks", "directory the blocks are cached in")
+	backtestCommand.Flags().BoolVar(&backtestOptions.offline, "offline", false, "only use cached blocks")
+	backtestCommand.Flags().StringVar(&backtestOptions.outputDir, "outputDir", "./output/backtest", "directory the scores are written to")
+	backtestCommand.MarkFlagRequired("from")
+	backtestCommand.MarkFlagRequired("to")
+
+	addNaiveFlags(backtestCommand.Flags())
+	addExpressFlags(backtestCommand.Flags())
+	addWeb3jFlags(backtestCommand.Flags())
+}
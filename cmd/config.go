@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// applyConfig sets the flags of the command from a json file mapping flag
+// names to values, e.g. written by tune. Flags given on the command line
+// take precedence.
+func applyConfig(cmd *cobra.Command, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() //keeps wei values as integers
+	err = decoder.Decode(&values)
+	if err != nil {
+		return fmt.Errorf("could not parse config %v: %v", path, err)
+	}
+
+	flags := cmd.Flags()
+	for name, value := range values {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("config %v: %v has no flag %v", path, cmd.Name(), name)
+		}
+		if flag.Changed {
+			continue
+		}
+
+		err = flags.Set(name, fmt.Sprint(value))
+		if err != nil {
+			return fmt.Errorf("config %v: %v", path, err)
+		}
+	}
+
+	return nil
+}
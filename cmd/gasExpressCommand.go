@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/mariusgiger/ethereum-feeestimator/pkg/gasstation/express"
 )
 
@@ -10,11 +11,28 @@ var gasExpressCmd = &cobra.Command{
 	Short: "Suggests a gas price using the gas station express algorithm",
 	Long:  `Suggests a gas price using the gas station express algorithm.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		estimator := express.NewEstimator(logger, rpcClient)
+		scores, err := newScores("express", express.Tiers)
+		if err != nil {
+			return err
+		}
+
+		estimator := express.NewEstimator(logger, expressOptions, rpcClient, classifier, scores)
 		return estimator.Run()
 	},
 }
 
+var (
+	expressOptions = express.DefaultConfig
+)
+
 func init() {
 	RootCmd.AddCommand(gasExpressCmd)
+	addExpressFlags(gasExpressCmd.Flags())
+}
+
+// addExpressFlags registers the parameters of the express estimator
+func addExpressFlags(flags *pflag.FlagSet) {
+	flags.Uint64Var(&expressOptions.SafeLow, "safeLow", express.DefaultConfig.SafeLow, "% of the recent blocks accepting the slow price")
+	flags.Uint64Var(&expressOptions.Standard, "standard", express.DefaultConfig.Standard, "% of the recent blocks accepting the standard price")
+	flags.Uint64Var(&expressOptions.Fast, "fast", express.DefaultConfig.Fast, "% of the recent blocks accepting the fast price")
 }
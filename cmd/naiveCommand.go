@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/mariusgiger/ethereum-feeestimator/pkg/naive"
 )
 
@@ -11,11 +14,12 @@ var naiveCmd = &cobra.Command{
 	Short: "Suggests a naive gas price",
 	Long:  `Suggests a naive gas price.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config := gasprice.Config{
-			Blocks:     naiveOptions.numberOfBlocks,
-			Percentile: naiveOptions.percentile,
+		scores, err := newScores("naive", naive.Tiers)
+		if err != nil {
+			return err
 		}
-		estimator := naive.NewEstimator(logger, config, rpcClient)
+
+		estimator := naive.NewEstimator(logger, naiveConfig(), rpcClient, classifier, scores)
 		return estimator.Run()
 	},
 }
@@ -24,13 +28,39 @@ var (
 	naiveOptions struct {
 		numberOfBlocks int
 		percentile     int
+		samples        int
+		ignorePrice    int64
+		maxPrice       int64
+		defaultPrice   int64
 	}
 )
 
+// naiveConfig returns the config of the naive estimator set by the flags
+func naiveConfig() naive.Config {
+	return naive.Config{
+		Config: gasprice.Config{
+			Blocks:      naiveOptions.numberOfBlocks,
+			Percentile:  naiveOptions.percentile,
+			Default:     big.NewInt(naiveOptions.defaultPrice),
+			MaxPrice:    big.NewInt(naiveOptions.maxPrice),
+			IgnorePrice: big.NewInt(naiveOptions.ignorePrice),
+		},
+		Samples: naiveOptions.samples,
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(naiveCmd)
+	addNaiveFlags(naiveCmd.Flags())
+}
 
+// addNaiveFlags registers the parameters of the naive estimator
+func addNaiveFlags(flags *pflag.FlagSet) {
 	//TODO find a good value
-	naiveCmd.Flags().IntVarP(&naiveOptions.numberOfBlocks, "numberOfBlocks", "n", 20, "number of blocks that are used for the estimate")
-	naiveCmd.Flags().IntVarP(&naiveOptions.percentile, "percentile", "p", 60, "percentile of gas prices to be used (value between 1-100, higher is safer)")
+	flags.IntVarP(&naiveOptions.numberOfBlocks, "numberOfBlocks", "n", naive.DefaultConfig.Blocks, "number of blocks that are used for the estimate")
+	flags.IntVarP(&naiveOptions.percentile, "percentile", "p", naive.DefaultConfig.Percentile, "percentile of gas prices to be used (value between 1-100, higher is safer)")
+	flags.IntVarP(&naiveOptions.samples, "samples", "s", naive.DefaultConfig.Samples, "number of cheapest transactions sampled per block")
+	flags.Int64Var(&naiveOptions.ignorePrice, "ignorePrice", naive.DefaultConfig.IgnorePrice.Int64(), "gas prices (in wei) below this value are ignored")
+	flags.Int64Var(&naiveOptions.maxPrice, "maxPrice", naive.DefaultConfig.MaxPrice.Int64(), "maximum gas price (in wei) that is suggested")
+	flags.Int64Var(&naiveOptions.defaultPrice, "defaultPrice", naive.DefaultConfig.Default.Int64(), "gas price (in wei) used for empty blocks until a price is known")
 }
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/oracle"
+)
+
+var oracleCommand = &cobra.Command{
+	Use:   "oracle",
+	Short: "Records the gas prices suggested by the oracles of nodes",
+	Long: `Polls eth_gasPrice, eth_maxPriorityFeePerGas and eth_feeHistory of the
+configured endpoints every block. The values are scored like an estimator
+named after the endpoint, so the estimators can be compared to the oracles
+built into the nodes.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endpoints := []oracle.Endpoint{{Name: "node", URL: rootOptions.node}}
+		if oracleOptions.endpoints != "" {
+			var err error
+			endpoints, err = oracle.LoadEndpoints(oracleOptions.endpoints)
+			if err != nil {
+				return err
+			}
+		}
+
+		oracles := make([]*oracle.Oracle, len(endpoints))
+		for i, endpoint := range endpoints {
+			scores, err := newScores(endpoint.Name, oracle.TierNames(endpoint))
+			if err != nil {
+				return err
+			}
+			defer scores.Close()
+
+			oracles[i] = oracle.NewOracle(logger, endpoint, oracle.Dial(endpoint), rpcClient, scores)
+		}
+
+		errorChannel := make(chan error, len(oracles))
+		for _, o := range oracles {
+			go func(o *oracle.Oracle) {
+				errorChannel <- o.Run()
+			}(o)
+		}
+
+		return <-errorChannel
+	},
+}
+
+var (
+	oracleOptions struct {
+		endpoints string
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(oracleCommand)
+	oracleCommand.Flags().StringVar(&oracleOptions.endpoints, "endpoints", "", "path to a json file containing the endpoints (name, url, methods, feeHistoryBlocks, percentiles, local)")
+}
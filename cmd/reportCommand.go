@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/report"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var reportCommand = &cobra.Command{
+	Use:   "report [score files or directories]",
+	Short: "Compares the estimators based on their scores",
+	Long: `Compares the estimators based on their scores. Reads the jsonl and csv score
+files (by default in ./output) and writes an HTML report, or Markdown if --out
+ends with .md, containing the hit rates and overpayments per tier, paired bootstrap
+comparisons between the estimators and charts of the predicted prices.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths := args
+		if len(paths) == 0 {
+			paths = []string{"./output"}
+		}
+
+		records, err := report.Load(paths)
+		if err != nil {
+			return err
+		}
+
+		bootstrap := report.NewBootstrap(reportOptions.iterations, reportOptions.confidence, reportOptions.seed)
+		r := report.New(records, bootstrap)
+
+		f, err := os.Create(reportOptions.out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		if filepath.Ext(reportOptions.out) == ".md" {
+			err = r.WriteMarkdown(f)
+		} else {
+			err = r.WriteHTML(f)
+		}
+		if err != nil {
+			return err
+		}
+
+		logger.Info("report written", zap.String("path", reportOptions.out), zap.Int("records", len(records)))
+		return f.Close()
+	},
+}
+
+var (
+	reportOptions struct {
+		out        string
+		iterations int
+		confidence float64
+		seed       int64
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(reportCommand)
+
+	reportCommand.Flags().StringVarP(&reportOptions.out, "out", "o", "./output/report.html", "path of the report, Markdown if it ends with .md")
+	reportCommand.Flags().IntVar(&reportOptions.iterations, "iterations", 1000, "number of bootstrap resamples")
+	reportCommand.Flags().Float64Var(&reportOptions.confidence, "confidence", 0.95, "confidence level of the intervals")
+	reportCommand.Flags().Int64Var(&reportOptions.seed, "seed", 1, "seed of the bootstrap")
+}
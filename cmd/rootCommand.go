@@ -1,9 +1,17 @@
 package cmd
 
 import (
+	"fmt"
+	"math/big"
 	"os"
+	"time"
 
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/estimator"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/gasstation/express"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/naive"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
 	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/web3j"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -11,8 +19,22 @@ import (
 )
 
 var (
-	logger    *zap.Logger
-	rpcClient *utils.CachedRPCClient
+	logger     *zap.Logger
+	rpcClient  *utils.CachedRPCClient
+	classifier *utils.TxClassifier
+
+	rootOptions struct {
+		node     string
+		logFile  string
+		chainID  int64
+		horizon  int
+		txVolume int64
+
+		rotateRows  int
+		rotateAfter time.Duration
+		formats     []string
+		config      string
+	}
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -20,26 +42,108 @@ var RootCmd = &cobra.Command{
 	Use:   "estimator",
 	Short: "Ethereum fee estimator",
 	Long:  `Ethereum fee estimator.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if rootOptions.config != "" {
+			err := applyConfig(cmd, rootOptions.config)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := newLogger(rootOptions.logFile)
+		if err != nil {
+			return err
+		}
+
+		rpcClient = utils.NewCachedRPCClient(rootOptions.node, logger)
+		classifier = utils.NewTxClassifier(big.NewInt(rootOptions.chainID))
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
-		logger.Fatal("Something somewhere went terribly wrong", zap.Error(err))
-		os.Exit(-1)
+		code := 1
+		if exit, ok := err.(*exitError); ok {
+			code = exit.code
+		}
+
+		if logger != nil {
+			logger.Error("Something somewhere went terribly wrong", zap.Error(err))
+		}
+		os.Exit(code)
 	}
 }
 
-func init() {
+// newLogger creates the logger writing to stderr and the log file, if set
+func newLogger(logFile string) error {
 	cfg := zap.NewDevelopmentConfig()
-	cfg.OutputPaths = append(cfg.OutputPaths, "./output/estimator.log")
+	if logFile != "" {
+		cfg.OutputPaths = append(cfg.OutputPaths, logFile)
+	}
 
 	var err error
 	logger, err = cfg.Build(zap.AddStacktrace(zapcore.DPanicLevel))
 	if err != nil {
-		panic("could not create logger")
+		return fmt.Errorf("could not create logger: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&rootOptions.node, "node", utils.NodeURL, "JSON-RPC url of the node the blocks are loaded from")
+	RootCmd.PersistentFlags().StringVar(&rootOptions.logFile, "logFile", "./output/estimator.log", "file the log is written to in addition to stderr (empty disables)")
+	RootCmd.PersistentFlags().Int64Var(&rootOptions.chainID, "chainId", 1, "chain id used to recover transaction senders")
+	RootCmd.PersistentFlags().Int64Var(&rootOptions.txVolume, "txVolume", 1, "assumed number of transactions sent per prediction, used to aggregate the overspend")
+	RootCmd.PersistentFlags().IntVar(&rootOptions.horizon, "horizon", scoring.DefaultHorizon, "number of blocks after a prediction it is scored against")
+	RootCmd.PersistentFlags().IntVar(&rootOptions.rotateRows, "rotateRows", 0, "number of rows after which a new score file is started (0 disables)")
+	RootCmd.PersistentFlags().DurationVar(&rootOptions.rotateAfter, "rotateAfter", 24*time.Hour, "age after which a new score file is started (0 disables)")
+	RootCmd.PersistentFlags().StringVar(&rootOptions.config, "config", "", "path to a json file mapping flag names to values, e.g. written by tune")
+	RootCmd.PersistentFlags().StringSliceVar(&rootOptions.formats, "format", []string{scoring.DefaultFormat}, "formats the scores are written in (csv, jsonl, parquet)")
+}
+
+// estimatorOptions returns the options of the named estimator set by the flags
+// and its tiers
+func estimatorOptions(name string) ([]estimator.Option, []string, error) {
+	options := []estimator.Option{
+		estimator.WithLogger(logger),
+		estimator.WithClassifier(classifier),
+	}
+
+	switch name {
+	case estimator.Naive:
+		return append(options, estimator.WithNaiveConfig(naiveConfig())), naive.Tiers, nil
+	case estimator.Express:
+		return append(options, estimator.WithExpressConfig(expressOptions)), express.Tiers, nil
+	case estimator.Web3j:
+		tiers, builders, err := web3jConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		return append(options, estimator.WithTiers(tiers), estimator.WithBuilders(builders)), web3j.TierNames(tiers), nil
+	default:
+		return nil, nil, fmt.Errorf("unknown estimator %v, supported are %v", name, estimator.Names)
 	}
+}
 
-	rpcClient = utils.NewCachedRPCClient(logger)
+// newScores creates the scores of an estimator with the given tiers
+func newScores(name string, tiers []string) (*scoring.Scores, error) {
+	return scoring.NewScores(scoreConfig(name, tiers), rpcClient, classifier, logger)
+}
+
+// scoreConfig returns the scoring config set by the flags
+func scoreConfig(name string, tiers []string) scoring.Config {
+	return scoring.Config{
+		Name:     name,
+		Tiers:    tiers,
+		Horizon:  rootOptions.horizon,
+		TxVolume: rootOptions.txVolume,
+		Formats:  rootOptions.formats,
+		Rotation: scoring.Rotation{
+			MaxRows: rootOptions.rotateRows,
+			MaxAge:  rootOptions.rotateAfter,
+		},
+	}
 }
@@ -0,0 +1,232 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/estimator"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/feeestimatorpb"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/server"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+var serveCommand = &cobra.Command{
+	Use:   "serve",
+	Short: "Runs the estimators and serves their estimates over HTTP",
+	Long: `Runs the selected estimators and serves their latest estimates as JSON:
+
+  GET /v1/estimates                       the estimates of all estimators
+  GET /v1/estimates/{estimator}           the estimate of one estimator
+  GET /v1/estimates/{estimator}/{format}  the estimate in a compatibility format
+
+The formats ethgasstation, etherscan and eip1559 render the estimate like the
+oracles clients already parse, they can also be selected with ?format= or the
+Accept header (application/json; format=etherscan). The predictions are scored
+like with the estimator commands.
+
+Every new estimate is pushed to the subscribers of
+
+  GET /v1/stream     Server-Sent Events
+  GET /v1/stream/ws  WebSocket
+
+which filter with ?estimator=naive,web3j and ?tier=fast. Slow subscribers
+only receive the latest estimate per estimator.
+
+With --rpcListen a JSON-RPC facade of the node is served in addition. It answers
+eth_gasPrice, eth_maxPriorityFeePerGas and eth_feeHistory from the estimate of
+--rpcEstimator and forwards every other method to the node.
+
+With --grpcListen the FeeEstimator gRPC service (pkg/feeestimatorpb) is served
+in addition, its probability curves are answered by the web3j estimator.
+
+GET /healthz reports the liveness. GET /readyz (or /readyz/{estimator}) fails
+with 503 if an estimate is older than --readyMaxAge or more than
+--readyMaxBlocks behind the head, if the last run of an estimator failed or if
+the circuit of the node is open. The head is loaded from the node at most once
+per --readyHeadMaxAge. Failed runs do not stop the server, they are retried
+every interval.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveOptions.rpcListen != "" && !contains(serveOptions.estimators, serveOptions.rpcEstimator) {
+			return fmt.Errorf("rpcEstimator %v is not one of the served estimators %v", serveOptions.rpcEstimator, serveOptions.estimators)
+		}
+
+		snapshot := server.NewSnapshot(nil)
+		estimators := make([]*estimator.Estimator, len(serveOptions.estimators))
+		health := server.HealthConfig{
+			Estimators:      make(map[string]server.ErrorSource),
+			MaxAge:          serveOptions.readyMaxAge,
+			MaxBlocksBehind: serveOptions.readyMaxBlocks,
+			Head:            rpcClient,
+			HeadMaxAge:      serveOptions.readyHeadMaxAge,
+			Circuit:         rpcClient.Breaker(),
+		}
+		for i, name := range serveOptions.estimators {
+			var scores *scoring.Scores
+			var err error
+			estimators[i], scores, err = newServedEstimator(cmd, name, snapshot)
+			if err != nil {
+				return err
+			}
+			defer scores.Close() //after the estimators are stopped
+			health.Estimators[name] = estimators[i]
+		}
+
+		//failed runs are retried every interval and reported by the readiness
+		for _, e := range estimators {
+			err := e.Start(context.Background())
+			if err != nil {
+				return err
+			}
+			defer e.Stop()
+		}
+
+		handler := server.NewHandler(snapshot, logger)
+		handler.ServeHealth(health)
+
+		errorChannel := make(chan error, 3) //servers
+		go func() {
+			logger.Info("serving estimates", zap.String("listen", serveOptions.listen))
+			errorChannel <- http.ListenAndServe(serveOptions.listen, handler)
+		}()
+
+		if serveOptions.rpcListen != "" {
+			rpcHandler := server.NewRPCHandler(snapshot, serveOptions.rpcEstimator, serveOptions.rpcTier, server.NewHTTPProxy(rootOptions.node), logger)
+			go func() {
+				logger.Info("serving json-rpc facade", zap.String("listen", serveOptions.rpcListen), zap.String("estimator", serveOptions.rpcEstimator))
+				errorChannel <- http.ListenAndServe(serveOptions.rpcListen, rpcHandler)
+			}()
+		}
+
+		if serveOptions.grpcListen != "" {
+			listener, err := net.Listen("tcp", serveOptions.grpcListen)
+			if err != nil {
+				return err
+			}
+
+			var curves server.CurveSource
+			for _, e := range estimators {
+				if c, ok := e.Unwrap().(server.CurveSource); ok {
+					curves = c
+				}
+			}
+
+			grpcServer := grpc.NewServer()
+			feeestimatorpb.RegisterFeeEstimatorServer(grpcServer, server.NewGRPCService(snapshot, curves))
+			go func() {
+				logger.Info("serving grpc", zap.String("listen", serveOptions.grpcListen))
+				errorChannel <- grpcServer.Serve(listener)
+			}()
+		}
+
+		return <-errorChannel
+	},
+}
+
+var (
+	serveOptions struct {
+		listen     string
+		estimators []string
+
+		rpcListen    string
+		rpcEstimator string
+		rpcTier      string
+
+		grpcListen string
+
+		readyMaxAge     time.Duration
+		readyMaxBlocks  int64
+		readyHeadMaxAge time.Duration
+	}
+
+	//serveFlags maps the estimators to the names of their flags
+	serveFlags = make(map[string][]string)
+)
+
+// newServedEstimator creates the named estimator on the node, its predictions
+// are published to the snapshot and scored, the scores have to be closed
+func newServedEstimator(cmd *cobra.Command, name string, snapshot *server.Snapshot) (*estimator.Estimator, *scoring.Scores, error) {
+	options, tiers, err := estimatorOptions(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	scores, err := newScores(name, tiers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publisher := server.NewPublisher(snapshot, rpcClient, name, tiers, flagValues(cmd.Flags(), serveFlags[name]), scores)
+	e, err := estimator.New(name, rpcClient, append(options, estimator.WithRecorder(publisher))...)
+	if err != nil {
+		scores.Close()
+		return nil, nil, err
+	}
+	return e, scores, nil
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addFlagsOf registers the flags added by add and returns their names
+func addFlagsOf(flags *pflag.FlagSet, add func(*pflag.FlagSet)) []string {
+	existing := make(map[string]bool)
+	flags.VisitAll(func(flag *pflag.Flag) {
+		existing[flag.Name] = true
+	})
+
+	add(flags)
+
+	var names []string
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if !existing[flag.Name] {
+			names = append(names, flag.Name)
+		}
+	})
+	return names
+}
+
+// flagValues returns the current values of the named flags
+func flagValues(flags *pflag.FlagSet, names []string) map[string]string {
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		if flag := flags.Lookup(name); flag != nil {
+			values[name] = flag.Value.String()
+		}
+	}
+
+	return values
+}
+
+func init() {
+	RootCmd.AddCommand(serveCommand)
+
+	serveCommand.Flags().StringVar(&serveOptions.listen, "listen", ":8080", "address the estimates are served on")
+	serveCommand.Flags().StringSliceVarP(&serveOptions.estimators, "estimators", "e", []string{"naive", "express", "web3j"}, "estimators to run")
+	serveCommand.Flags().StringVar(&serveOptions.rpcListen, "rpcListen", "", "address the json-rpc facade of the node is served on (empty disables it)")
+	serveCommand.Flags().StringVar(&serveOptions.rpcEstimator, "rpcEstimator", "web3j", "estimator answering the fee methods of the json-rpc facade")
+	serveCommand.Flags().StringVar(&serveOptions.rpcTier, "rpcTier", "", "tier answering eth_gasPrice and eth_maxPriorityFeePerGas (empty selects the standard speed)")
+	serveCommand.Flags().StringVar(&serveOptions.grpcListen, "grpcListen", "", "address the grpc service is served on (empty disables it)")
+	serveCommand.Flags().DurationVar(&serveOptions.readyMaxAge, "readyMaxAge", 2*time.Minute, "age after which an estimate is stale and /readyz fails (0 disables)")
+	serveCommand.Flags().Int64Var(&serveOptions.readyMaxBlocks, "readyMaxBlocks", 10, "blocks an estimate may be behind the head before /readyz fails (0 disables)")
+	serveCommand.Flags().DurationVar(&serveOptions.readyHeadMaxAge, "readyHeadMaxAge", 5*time.Second, "age after which /readyz reloads the head from the node (0 reloads it on every probe)")
+
+	serveFlags["naive"] = addFlagsOf(serveCommand.Flags(), addNaiveFlags)
+	serveFlags["express"] = addFlagsOf(serveCommand.Flags(), addExpressFlags)
+	serveFlags["web3j"] = addFlagsOf(serveCommand.Flags(), addWeb3jFlags)
+}
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/backtest"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/estimator"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of suggest, other errors exit with 1
+const (
+	exitEstimateFailed = 2 //the blocks could not be loaded or the estimator failed
+	exitNoEstimate     = 3 //the estimator did not predict
+)
+
+var suggestCommand = &cobra.Command{
+	Use:   "suggest",
+	Short: "Prints one estimate and exits",
+	Long: `Estimates once at the current head (or at --block) and prints the prices of
+the tiers to stdout, logs are written to stderr only unless --logFile is set.
+Unlike for the other commands --format (-o) selects the output format, json,
+table (default) or csv:
+
+  estimator suggest --estimator express --format json
+
+The exit status is 0 if an estimate was printed, 2 if the blocks could not be
+loaded or the estimator failed, 3 if the estimator did not predict and 1 for
+any other error, e.g. invalid flags.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if !cmd.Flags().Changed("logFile") {
+			rootOptions.logFile = "" //the output is for scripts, keep it free of files
+		}
+		return RootCmd.PersistentPreRunE(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		render, ok := suggestFormats[suggestOptions.format]
+		if !ok {
+			return fmt.Errorf("unknown format %v, supported are json, table and csv", suggestOptions.format)
+		}
+
+		options, _, err := estimatorOptions(suggestOptions.estimator)
+		if err != nil {
+			return err
+		}
+		cmd.SilenceUsage = true //the flags are valid, later errors are no usage errors
+
+		var blocks utils.BlockSource = rpcClient
+		if suggestOptions.block > 0 {
+			chain := backtest.NewChain(rpcClient)
+			err = chain.SetHead(suggestOptions.block)
+			if err != nil {
+				return &exitError{code: exitEstimateFailed, err: fmt.Errorf("could not load block %v: %v", suggestOptions.block, err)}
+			}
+			blocks = chain
+			options = append(options, estimator.WithClock(chain))
+		}
+
+		e, err := estimator.New(suggestOptions.estimator, blocks, options...)
+		if err != nil {
+			return err
+		}
+
+		err = e.Step()
+		if err != nil {
+			return &exitError{code: exitEstimateFailed, err: err}
+		}
+		result, ok := e.Latest()
+		if !ok {
+			return &exitError{code: exitNoEstimate, err: fmt.Errorf("%v did not predict", suggestOptions.estimator)}
+		}
+
+		return render(cmd.OutOrStdout(), result)
+	},
+}
+
+var (
+	suggestOptions struct {
+		estimator string
+		format    string
+		block     int64
+	}
+
+	//suggestFormats maps the formats to their renderers
+	suggestFormats = map[string]func(w io.Writer, result *estimator.Result) error{
+		"json":  renderSuggestJSON,
+		"table": renderSuggestTable,
+		"csv":   renderSuggestCSV,
+	}
+)
+
+// exitError ends the program with the given exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+type suggestTier struct {
+	Name string  `json:"name"`
+	Wei  int64   `json:"wei"`
+	Gwei float64 `json:"gwei"`
+}
+
+type suggestion struct {
+	Estimator   string        `json:"estimator"`
+	BlockNumber int64         `json:"blockNumber"`
+	UpdatedAt   time.Time     `json:"updatedAt"`
+	Tiers       []suggestTier `json:"tiers"`
+}
+
+func renderSuggestJSON(w io.Writer, result *estimator.Result) error {
+	tiers := make([]suggestTier, len(result.Tiers))
+	for i, tier := range result.Tiers {
+		price := result.Prices[tier]
+		tiers[i] = suggestTier{Name: tier, Wei: price, Gwei: float64(price) / utils.GWei}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(suggestion{
+		Estimator:   result.Estimator,
+		BlockNumber: result.BlockNumber,
+		UpdatedAt:   result.UpdatedAt,
+		Tiers:       tiers,
+	})
+}
+
+func renderSuggestTable(w io.Writer, result *estimator.Result) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(table, "%v at block %v\n", result.Estimator, result.BlockNumber)
+	fmt.Fprintln(table, "TIER\tWEI\tGWEI")
+	for _, tier := range result.Tiers {
+		price := result.Prices[tier]
+		fmt.Fprintf(table, "%v\t%v\t%.2f\n", tier, price, float64(price)/utils.GWei)
+	}
+
+	return table.Flush()
+}
+
+func renderSuggestCSV(w io.Writer, result *estimator.Result) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"estimator", "blockNumber", "tier", "wei", "gwei"})
+	for _, tier := range result.Tiers {
+		price := result.Prices[tier]
+		writer.Write([]string{
+			result.Estimator,
+			strconv.FormatInt(result.BlockNumber, 10),
+			tier,
+			strconv.FormatInt(price, 10),
+			strconv.FormatFloat(float64(price)/utils.GWei, 'f', -1, 64),
+		})
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func init() {
+	RootCmd.AddCommand(suggestCommand)
+
+	suggestCommand.Flags().StringVarP(&suggestOptions.estimator, "estimator", "e", estimator.Express, "estimator to suggest with (naive, express, web3j)")
+	//shadows the persistent --format of the scores, suggest writes none
+	suggestCommand.Flags().StringVarP(&suggestOptions.format, "format", "o", "table", "output format (json, table, csv)")
+	suggestCommand.Flags().Int64Var(&suggestOptions.block, "block", 0, "block to estimate at (0 is the current head)")
+
+	addNaiveFlags(suggestCommand.Flags())
+	addExpressFlags(suggestCommand.Flags())
+	addWeb3jFlags(suggestCommand.Flags())
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// node answers eth_getBlockByNumber from the blocks, the last one is the head
+func node(t *testing.T, blocks []*utils.Block) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			ID     interface{}   `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		require.Equal(t, "eth_getBlockByNumber", request.Method)
+
+		var result *utils.Block
+		if request.Params[0] == "latest" {
+			result = blocks[len(blocks)-1]
+		}
+		for _, block := range blocks {
+			if request.Params[0] == block.Number.String() {
+				result = block
+			}
+		}
+
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": request.ID, "result": result})
+	}))
+}
+
+func TestSuggestPrintsTheFormatOfItsFlag(t *testing.T) {
+	// arrange
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	var blocks []*utils.Block
+	for i := int64(1); i <= 3; i++ {
+		tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(2 * utils.GWei),
+			GasFeeCap: big.NewInt(100 * utils.GWei),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(0),
+		})
+		require.NoError(t, err)
+		blocks = append(blocks, &utils.Block{
+			Hash:         common.Hash{byte(i)},
+			ParentHash:   common.Hash{byte(i - 1)},
+			Miner:        common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5"),
+			Number:       (*hexutil.Big)(big.NewInt(i)),
+			BaseFee:      (*hexutil.Big)(big.NewInt(10 * utils.GWei)),
+			Time:         (*hexutil.Big)(big.NewInt(1600000000 + 12*i)),
+			Transactions: utils.Transactions{tx},
+		})
+	}
+	server := node(t, blocks)
+	defer server.Close()
+
+	var out bytes.Buffer
+	RootCmd.SetOutput(&out)
+	RootCmd.SetArgs([]string{"suggest", "--node", server.URL, "--estimator", "naive", "--numberOfBlocks", "3", "--format", "json"})
+
+	// act
+	err = RootCmd.Execute()
+
+	// assert
+	require.NoError(t, err)
+	var printed suggestion
+	require.NoError(t, json.Unmarshal(out.Bytes(), &printed))
+	assert.Equal(t, "naive", printed.Estimator)
+	assert.Equal(t, int64(3), printed.BlockNumber)
+	assert.Equal(t, []suggestTier{{Name: "standard", Wei: 12 * utils.GWei, Gwei: 12}}, printed.Tiers)
+	assert.Equal(t, []string{scoring.DefaultFormat}, rootOptions.formats, "the score formats are shadowed")
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/backtest"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/tuning"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+var tuneCommand = &cobra.Command{
+	Use:   "tune",
+	Short: "Searches the parameters of the estimators using backtests",
+	Long: `Searches the parameters of the estimators using backtests. The search space is
+a json file mapping estimators to the flags to tune, e.g.
+{"naive": {"numberOfBlocks": {"values": [10, 20, 40]}, "percentile": {"min": 40, "max": 90}}}.
+Every configuration of the grid (or --candidates random ones) is backtested over
+[--from, --to] and ranked by the objective on --tier. The best configuration is
+written to <outputDir>/<estimator>.json, which can be passed to --config.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		objective, err := tuning.LookupObjective(tuneOptions.objective)
+		if err != nil {
+			return err
+		}
+
+		spaces, err := tuning.LoadSpaces(tuneOptions.space)
+		if err != nil {
+			return err
+		}
+
+		store, err := newBlockStore(tuneOptions.blocks, tuneOptions.offline)
+		if err != nil {
+			return err
+		}
+
+		estimators := make([]string, 0, len(spaces))
+		for estimator := range spaces {
+			estimators = append(estimators, estimator)
+		}
+		sort.Strings(estimators)
+
+		rng := rand.New(rand.NewSource(tuneOptions.seed))
+		for _, estimator := range estimators {
+			err = tune(cmd, store, estimator, spaces[estimator], objective, rng)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+var (
+	tuneOptions struct {
+		from       int64
+		to         int64
+		space      string
+		random     bool
+		candidates int
+		seed       int64
+		objective  string
+		tier       string
+		minHitRate float64
+		blocks     string
+		offline    bool
+		outputDir  string
+	}
+)
+
+// tune backtests the candidates of the search space and writes the ranking
+// and the best configuration of the estimator
+func tune(cmd *cobra.Command, store *backtest.BlockStore, estimator string, space tuning.Space, objective *tuning.Objective, rng *rand.Rand) error {
+	err := space.Validate(tuneOptions.random)
+	if err != nil {
+		return fmt.Errorf("%v: %v", estimator, err)
+	}
+
+	candidates := space.Grid()
+	if tuneOptions.random {
+		candidates = space.Random(tuneOptions.candidates, rng)
+	}
+	if len(candidates) == 0 {
+		return fmt.Errorf("%v: the search space has no candidates", estimator)
+	}
+
+	results := make([]*tuning.Result, 0, len(candidates))
+	for i, candidate := range candidates {
+		result, err := backtestCandidate(cmd.Flags(), store, estimator, i, candidate, objective)
+		if err != nil {
+			return err
+		}
+
+		results = append(results, result)
+		logger.Info("candidate backtested", zap.String("estimator", estimator), zap.Int("candidate", i+1), zap.Int("of", len(candidates)),
+			zap.Any("parameters", candidate), zap.Float64(objective.Name, result.Value), zap.Bool("feasible", result.Feasible))
+	}
+
+	tuning.Rank(results, objective)
+	err = tuning.WriteRanking(filepath.Join(tuneOptions.outputDir, estimator+"ranking.csv"), space, results)
+	if err != nil {
+		return err
+	}
+
+	best := results[0]
+	if !best.Feasible {
+		logger.Warn("no configuration reached the minimum hit rate", zap.String("estimator", estimator), zap.Float64("minHitRate", tuneOptions.minHitRate))
+	}
+
+	path := filepath.Join(tuneOptions.outputDir, estimator+".json")
+	logger.Info("best configuration", zap.String("estimator", estimator), zap.Any("parameters", best.Candidate), zap.Float64(objective.Name, best.Value), zap.String("config", path))
+	return tuning.WriteConfig(path, best.Candidate)
+}
+
+// backtestCandidate backtests the estimator with the flags set to the values
+// of the i'th candidate. The flags are restored afterwards, so neither the
+// values nor the changed state leak into the next candidate or estimator.
+func backtestCandidate(flags *pflag.FlagSet, store *backtest.BlockStore, estimator string, i int, candidate tuning.Candidate, objective *tuning.Objective) (*tuning.Result, error) {
+	restore, err := setFlags(flags, candidate)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", estimator, err)
+	}
+	defer restore()
+
+	outputDir := filepath.Join(tuneOptions.outputDir, estimator, strconv.Itoa(i))
+	err = os.MkdirAll(outputDir, 0770)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := backtest.NewChain(store)
+	subject, err := newBacktestSubject(estimator, chain, outputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	bt := backtest.NewBacktest(chain, []*backtest.Subject{subject}, rootOptions.horizon, logger)
+	err = bt.Run(tuneOptions.from, tuneOptions.to)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, ok := bt.Summary()[estimator][tuneOptions.tier]
+	if !ok {
+		return nil, fmt.Errorf("%v does not predict the tier %v", estimator, tuneOptions.tier)
+	}
+
+	return tuning.NewResult(candidate, summary, objective, tuneOptions.minHitRate), nil
+}
+
+// setFlags sets the flags of the candidate and returns a func which restores
+// their previous values and changed state
+func setFlags(flags *pflag.FlagSet, candidate tuning.Candidate) (func(), error) {
+	var restores []func()
+	restore := func() {
+		for _, r := range restores {
+			r()
+		}
+	}
+
+	for name, value := range candidate {
+		flag := flags.Lookup(name)
+		if flag == nil {
+			restore()
+			return nil, fmt.Errorf("unknown flag %v", name)
+		}
+
+		previous, changed := flag.Value.String(), flag.Changed
+		restores = append(restores, func() {
+			flag.Value.Set(previous)
+			flag.Changed = changed
+		})
+
+		err := flags.Set(name, strconv.FormatInt(value, 10))
+		if err != nil {
+			restore()
+			return nil, err
+		}
+	}
+
+	return restore, nil
+}
+
+func init() {
+	RootCmd.AddCommand(tuneCommand)
+
+	tuneCommand.Flags().Int64Var(&tuneOptions.from, "from", 0, "first head of the backtests")
+	tuneCommand.Flags().Int64Var(&tuneOptions.to, "to", 0, "last head of the backtests")
+	tuneCommand.Flags().StringVar(&tuneOptions.space, "space", "", "path to a json file containing the search space per estimator")
+	tuneCommand.Flags().BoolVar(&tuneOptions.random, "random", false, "sample random configurations instead of searching the grid")
+	tuneCommand.Flags().IntVar(&tuneOptions.candidates, "candidates", 20, "number of random configurations")
+	tuneCommand.Flags().Int64Var(&tuneOptions.seed, "seed", 1, "seed of the random search")
+	tuneCommand.Flags().StringVar(&tuneOptions.objective, "objective", "hitRate", fmt.Sprintf("objective the configurations are ranked by %v", tuning.ObjectiveNames()))
+	tuneCommand.Flags().StringVar(&tuneOptions.tier, "tier", "standard", "tier the objective is computed on")
+	tuneCommand.Flags().Float64Var(&tuneOptions.minHitRate, "minHitRate", tuning.DefaultMinHitRate, "configurations below this hit rate (0-1) or never included rank last")
+	tuneCommand.Flags().StringVar(&tuneOptions.blocks, "blocks", "./output/blocks", "directory the blocks are cached in")
+	tuneCommand.Flags().BoolVar(&tuneOptions.offline, "offline", false, "only use cached blocks")
+	tuneCommand.Flags().StringVar(&tuneOptions.outputDir, "outputDir", "./output/tune", "directory the scores, rankings and configurations are written to")
+	tuneCommand.MarkFlagRequired("from")
+	tuneCommand.MarkFlagRequired("to")
+	tuneCommand.MarkFlagRequired("space")
+
+	addNaiveFlags(tuneCommand.Flags())
+	addExpressFlags(tuneCommand.Flags())
+	addWeb3jFlags(tuneCommand.Flags())
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
 	"github.com/mariusgiger/ethereum-feeestimator/pkg/web3j"
 )
 
@@ -10,11 +12,83 @@ var web3jCommand = &cobra.Command{
 	Short: "Suggests a gas price using the time based web3j algorithm",
 	Long:  `Suggests a gas price using the time based web3j algorithm.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		estimator := web3j.NewEstimator(logger, rpcClient)
+		tiers, builders, err := web3jConfig()
+		if err != nil {
+			return err
+		}
+
+		scores, err := newScores("web3j", web3j.TierNames(tiers))
+		if err != nil {
+			return err
+		}
+
+		estimator := web3j.NewEstimator(logger, rpcClient, classifier, tiers, builders, scores)
 		return estimator.Run()
 	},
 }
 
+var (
+	web3jOptions struct {
+		tiers       string
+		builders    string
+		sampleSize  int64
+		probability int
+	}
+)
+
+// web3jConfig loads the tiers and builders set by the flags
+func web3jConfig() ([]web3j.Tier, *utils.BuilderRegistry, error) {
+	tiers := web3j.DefaultTiers
+	if web3jOptions.tiers != "" {
+		var err error
+		tiers, err = web3j.LoadTiers(web3jOptions.tiers)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if web3jOptions.sampleSize > 0 || web3jOptions.probability > 0 {
+		tiers = append([]web3j.Tier(nil), tiers...)
+		for i := range tiers {
+			if web3jOptions.sampleSize > 0 {
+				tiers[i].SampleSize = web3jOptions.sampleSize
+			}
+			if web3jOptions.probability > 0 {
+				tiers[i].Probability = web3jOptions.probability
+			}
+		}
+
+		err := web3j.ValidateTiers(tiers)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rules := utils.DefaultBuilderRules
+	if web3jOptions.builders != "" {
+		var err error
+		rules, err = utils.LoadBuilderRules(web3jOptions.builders)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	builders, err := utils.NewBuilderRegistry(rules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return tiers, builders, nil
+}
+
 func init() {
 	RootCmd.AddCommand(web3jCommand)
+	addWeb3jFlags(web3jCommand.Flags())
+}
+
+// addWeb3jFlags registers the parameters of the web3j estimator
+func addWeb3jFlags(flags *pflag.FlagSet) {
+	flags.StringVarP(&web3jOptions.tiers, "tiers", "t", "", "path to a json file containing the tiers (name, maxWaitSeconds, sampleSize, probability)")
+	flags.StringVarP(&web3jOptions.builders, "builders", "b", "", "path to a json file mapping extraData patterns and fee recipients to builders (name, extraData, feeRecipients)")
+	flags.Int64Var(&web3jOptions.sampleSize, "sampleSize", 0, "number of blocks sampled by every tier (0 keeps the sample size of the tiers)")
+	flags.IntVar(&web3jOptions.probability, "probability", 0, "probability (0-100) of every tier (0 keeps the probability of the tiers)")
 }
@@ -0,0 +1,98 @@
+// Package backtest replays a historical block range to evaluate estimators
+// without running them live.
+package backtest
+
+import (
+	"errors"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"go.uber.org/zap"
+)
+
+// Estimator is stepped once per simulated head, it predicts using the chain
+// it was created with and feeds its scores
+type Estimator interface {
+	Step() error
+}
+
+// Subject is an estimator and its scores
+type Subject struct {
+	Name      string
+	Estimator Estimator
+	Scores    *scoring.Scores
+}
+
+// Backtest steps the head of a chain through a block range
+type Backtest struct {
+	chain    *Chain
+	subjects []*Subject
+	horizon  int
+	logger   *zap.Logger
+}
+
+// NewBacktest creates a backtest of the given subjects, the horizon has to match
+// the one of the scores
+func NewBacktest(chain *Chain, subjects []*Subject, horizon int, logger *zap.Logger) *Backtest {
+	return &Backtest{
+		chain:    chain,
+		subjects: subjects,
+		horizon:  horizon,
+		logger:   logger,
+	}
+}
+
+// Run steps every estimator at each head in [from, to]. The head then
+// advances horizon blocks further, so the last predictions are scored
+// completely, and the score files are closed.
+func (b *Backtest) Run(from int64, to int64) error {
+	if from > to {
+		return errors.New("the range is empty")
+	}
+
+	err := b.run(from, to)
+	for _, subject := range b.subjects {
+		closeErr := subject.Scores.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+func (b *Backtest) run(from int64, to int64) error {
+	for head := from; head <= to+int64(b.horizon); head++ {
+		err := b.chain.SetHead(head)
+		if err != nil {
+			return err
+		}
+
+		for _, subject := range b.subjects {
+			if head <= to {
+				err = subject.Estimator.Step()
+			} else {
+				err = subject.Scores.PredictScores()
+			}
+			if err != nil {
+				b.logger.Error("backtest failed", zap.String("estimator", subject.Name), zap.Int64("head", head), zap.Error(err))
+				return err
+			}
+		}
+
+		if (head-from)%100 == 0 {
+			b.logger.Info("backtest progress", zap.Int64("head", head), zap.Int64("from", from), zap.Int64("to", to))
+		}
+	}
+
+	return nil
+}
+
+// Summary returns the inclusion summary per estimator and tier
+func (b *Backtest) Summary() map[string]map[string]*scoring.InclusionSummary {
+	summary := make(map[string]map[string]*scoring.InclusionSummary, len(b.subjects))
+	for _, subject := range b.subjects {
+		summary[subject.Name] = subject.Scores.InclusionSummary()
+	}
+
+	return summary
+}
@@ -0,0 +1,81 @@
+package backtest
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySource is a canonical chain of blocks 0..n with a block every 12s
+type memorySource map[int64]*utils.Block
+
+func newMemorySource(n int64) memorySource {
+	source := make(memorySource)
+	for i := int64(0); i <= n; i++ {
+		source[i] = &utils.Block{
+			Hash:       common.BigToHash(big.NewInt(i + 1000)),
+			ParentHash: common.BigToHash(big.NewInt(i + 999)),
+			Number:     (*hexutil.Big)(big.NewInt(i)),
+			Time:       (*hexutil.Big)(big.NewInt(1600000000 + 12*i)),
+		}
+	}
+
+	return source
+}
+
+func (s memorySource) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	block, ok := s[blockNumber.Int64()]
+	if !ok {
+		return nil, utils.ErrBlockNotFound
+	}
+
+	return block, nil
+}
+
+func TestChainHidesBlocksAfterHead(t *testing.T) {
+	// arrange
+	source := newMemorySource(20)
+	chain := NewChain(source)
+
+	// act
+	require.NoError(t, chain.SetHead(10))
+	latest, err := chain.GetLastestBlock()
+	require.NoError(t, err)
+	_, futureErr := chain.GetBlockByNumber(big.NewInt(11))
+	parent, parentErr := chain.GetBlockByHash(latest.ParentHash)
+
+	// assert
+	assert.Equal(t, int64(10), latest.Number.ToInt().Int64())
+	assert.Equal(t, utils.ErrBlockNotFound, futureErr)
+	require.NoError(t, parentErr)
+	assert.Equal(t, int64(9), parent.Number.ToInt().Int64())
+	assert.Equal(t, time.Unix(1600000120, 0), chain.Now())
+}
+
+func TestBlockStoreServesCachedBlocksOffline(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	source := newMemorySource(5)
+	cached, err := NewBlockStore(dir, ModeCached, source)
+	require.NoError(t, err)
+	offline, err := NewBlockStore(dir, ModeOffline, nil)
+	require.NoError(t, err)
+
+	// act
+	_, err = cached.GetBlockByNumber(big.NewInt(3))
+	require.NoError(t, err)
+	block, err := offline.GetBlockByNumber(big.NewInt(3))
+	_, missingErr := offline.GetBlockByNumber(big.NewInt(4))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, source[3].Hash, block.Hash)
+	assert.Equal(t, int64(1600000036), block.Time.ToInt().Int64())
+	assert.Error(t, missingErr)
+}
@@ -0,0 +1,126 @@
+package backtest
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Chain replays historical blocks up to a simulated head. Blocks after the
+// head do not exist yet, so estimators only see data up to the head and the
+// scores are completed as the head advances. The time is the timestamp of
+// the head, which makes a backtest deterministic.
+type Chain struct {
+	source scoring.BlockSource
+	head   *utils.Block
+
+	//blocks served by number, used to resolve the (parent) hashes
+	numbers map[common.Hash]int64
+	mu      sync.RWMutex
+}
+
+// NewChain creates a chain replaying the blocks of source, SetHead has to be
+// called before it is used
+func NewChain(source scoring.BlockSource) *Chain {
+	return &Chain{
+		source:  source,
+		numbers: make(map[common.Hash]int64),
+	}
+}
+
+// SetHead moves the simulated head to the given block
+func (c *Chain) SetHead(number int64) error {
+	block, err := c.source.GetBlockByNumber(big.NewInt(number))
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.head = block
+	c.mu.Unlock()
+	c.remember(block)
+	return nil
+}
+
+func (c *Chain) headNumber() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.head == nil {
+		return -1
+	}
+
+	return c.head.Number.ToInt().Int64()
+}
+
+func (c *Chain) remember(block *utils.Block) {
+	number := block.Number.ToInt().Int64()
+	c.mu.Lock()
+	c.numbers[block.Hash] = number
+	if number > 0 {
+		c.numbers[block.ParentHash] = number - 1
+	}
+	c.mu.Unlock()
+}
+
+// GetLastestBlock returns the simulated head
+func (c *Chain) GetLastestBlock() (*utils.Block, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.head == nil {
+		return nil, utils.ErrBlockNotFound
+	}
+
+	return c.head, nil
+}
+
+// GetBlockByNumber returns utils.ErrBlockNotFound for blocks after the head
+func (c *Chain) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	if blockNumber.Int64() > c.headNumber() {
+		return nil, utils.ErrBlockNotFound
+	}
+
+	block, err := c.source.GetBlockByNumber(blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	c.remember(block)
+	return block, nil
+}
+
+// GetBlockByHash resolves the hashes of the blocks served and of their
+// parents, historical blocks are canonical so a parent is loaded by number
+func (c *Chain) GetBlockByHash(hash common.Hash) (*utils.Block, error) {
+	c.mu.RLock()
+	number, ok := c.numbers[hash]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown block hash %v", hash.Hex())
+	}
+
+	block, err := c.GetBlockByNumber(big.NewInt(number))
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash != hash {
+		return nil, fmt.Errorf("block %v has hash %v, expected %v", number, block.Hash.Hex(), hash.Hex())
+	}
+
+	return block, nil
+}
+
+// Now returns the timestamp of the head
+func (c *Chain) Now() time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.head == nil {
+		return time.Unix(0, 0)
+	}
+
+	return time.Unix(c.head.Time.ToInt().Int64(), 0)
+}
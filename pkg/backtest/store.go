@@ -0,0 +1,142 @@
+package backtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Mode determines where a BlockStore loads blocks from
+type Mode int
+
+const (
+	// ModeCached loads blocks from disk and fetches missing blocks from the
+	// upstream source, fetched blocks are written to disk
+	ModeCached Mode = iota
+
+	// ModeOffline only loads blocks from disk
+	ModeOffline
+)
+
+// MaxCachedBlocks limits the number of decoded blocks kept in memory per
+// cache generation
+var MaxCachedBlocks = 1024
+
+// ErrNotCached is returned in offline mode for blocks which are not on disk
+var ErrNotCached = errors.New("block is not cached")
+
+// BlockStore keeps historical blocks as <dir>/<number>.json, so a block
+// range has to be fetched from a node only once
+type BlockStore struct {
+	dir      string
+	mode     Mode
+	upstream scoring.BlockSource
+
+	//two generations are kept, the older one is dropped once the current is full
+	blocks     map[int64]*utils.Block
+	prevBlocks map[int64]*utils.Block
+	mu         sync.Mutex
+}
+
+// NewBlockStore creates a store in dir, upstream may be nil in offline mode
+func NewBlockStore(dir string, mode Mode, upstream scoring.BlockSource) (*BlockStore, error) {
+	if mode == ModeCached && upstream == nil {
+		return nil, errors.New("cached mode requires an upstream block source")
+	}
+
+	err := os.MkdirAll(dir, 0770)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockStore{
+		dir:        dir,
+		mode:       mode,
+		upstream:   upstream,
+		blocks:     make(map[int64]*utils.Block),
+		prevBlocks: make(map[int64]*utils.Block),
+	}, nil
+}
+
+// GetBlockByNumber loads the block from memory, disk or the upstream source
+func (s *BlockStore) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	number := blockNumber.Int64()
+	s.mu.Lock()
+	block, ok := s.blocks[number]
+	if !ok {
+		block, ok = s.prevBlocks[number]
+	}
+	s.mu.Unlock()
+	if ok {
+		return block, nil
+	}
+
+	block, err := s.load(number)
+	if os.IsNotExist(err) {
+		if s.mode == ModeOffline {
+			return nil, fmt.Errorf("%v: %v", ErrNotCached, number)
+		}
+
+		block, err = s.fetch(number)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if len(s.blocks) >= MaxCachedBlocks {
+		s.prevBlocks = s.blocks
+		s.blocks = make(map[int64]*utils.Block)
+	}
+	s.blocks[number] = block
+	s.mu.Unlock()
+	return block, nil
+}
+
+func (s *BlockStore) path(number int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%v.json", number))
+}
+
+func (s *BlockStore) load(number int64) (*utils.Block, error) {
+	data, err := os.ReadFile(s.path(number))
+	if err != nil {
+		return nil, err
+	}
+
+	block := new(utils.Block)
+	err = json.Unmarshal(data, block)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode cached block %v: %v", number, err)
+	}
+
+	return block, nil
+}
+
+// fetch loads the block from the upstream source and writes it to disk
+func (s *BlockStore) fetch(number int64) (*utils.Block, error) {
+	block, err := s.upstream.GetBlockByNumber(big.NewInt(number))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+
+	//write to a temporary file first so no partial blocks are cached
+	tmp := s.path(number) + ".tmp"
+	err = os.WriteFile(tmp, data, 0660)
+	if err != nil {
+		return nil, err
+	}
+
+	return block, os.Rename(tmp, s.path(number))
+}
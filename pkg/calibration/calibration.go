@@ -0,0 +1,211 @@
+// Package calibration checks the probabilities of inclusion claimed by the
+// estimators against the observed inclusions.
+package calibration
+
+import (
+	"math"
+	"sort"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+)
+
+// DefaultBins is the number of equally wide probability bins
+const DefaultBins = 10
+
+// Observation is the outcome of a prediction which claimed a probability of
+// inclusion within a window
+type Observation struct {
+	Estimator   string
+	Tier        string
+	Probability float64 //claimed, 0-1
+	Blocks      int64   //window, 0 if not limited in blocks
+	Seconds     int64   //window, 0 if not limited in seconds
+	Hit         bool    //included within the window
+
+	//Censored observations have a horizon ending before the window, so the
+	//outcome is unknown. They are censored whether or not an inclusion was
+	//seen, otherwise only the hits of long windows would be counted.
+	Censored bool
+}
+
+// Observe determines the outcome of a claim. inclusion is the first
+// inclusion within the horizon (nil if there was none) and the horizon is the
+// number of blocks and seconds observed after the prediction.
+func Observe(estimator string, tier string, claim *scoring.Claim, inclusion *scoring.Inclusion, horizonBlocks int64, horizonSeconds int64) *Observation {
+	observation := &Observation{
+		Estimator:   estimator,
+		Tier:        tier,
+		Probability: claim.Probability,
+		Blocks:      claim.Blocks,
+		Seconds:     claim.Seconds,
+	}
+
+	//the window ends with the first of its limits
+	covered := (claim.Blocks > 0 && horizonBlocks >= claim.Blocks) ||
+		(claim.Seconds > 0 && horizonSeconds >= claim.Seconds)
+	observation.Censored = !covered
+
+	if inclusion != nil {
+		observation.Hit = (claim.Blocks == 0 || inclusion.Blocks <= claim.Blocks) &&
+			(claim.Seconds == 0 || inclusion.Seconds <= claim.Seconds)
+	}
+	return observation
+}
+
+// Bin is a bucket of the claimed probabilities
+type Bin struct {
+	Low         float64
+	High        float64
+	Count       int
+	MeanClaimed float64
+	Observed    float64 //frequency of the hits
+}
+
+// Curve is the reliability curve of an estimator, a calibrated estimator
+// observes the claimed frequency in every bin
+type Curve struct {
+	Estimator string
+	Count     int //observations which are not censored
+	Censored  int
+	Bins      []*Bin //bins containing observations
+	ECE       float64
+}
+
+// Reliability buckets the observations of each estimator by the claimed
+// probability into equally wide bins and computes the expected calibration
+// error, i.e. the mean absolute difference between the claimed and observed
+// frequency weighted by the bin sizes
+func Reliability(observations []*Observation, bins int) []*Curve {
+	if bins < 1 {
+		bins = DefaultBins
+	}
+
+	curves := make(map[string]*Curve)
+	sums := make(map[string][]*Bin)
+	for _, observation := range observations {
+		curve, ok := curves[observation.Estimator]
+		if !ok {
+			curve = &Curve{Estimator: observation.Estimator}
+			curves[observation.Estimator] = curve
+			sums[observation.Estimator] = make([]*Bin, bins)
+		}
+		if observation.Censored {
+			curve.Censored++
+			continue
+		}
+		curve.Count++
+
+		idx := int(math.Floor(observation.Probability * float64(bins)))
+		if idx >= bins {
+			idx = bins - 1 //a probability of 1 belongs to the last bin
+		}
+		if idx < 0 {
+			idx = 0
+		}
+
+		bin := sums[observation.Estimator][idx]
+		if bin == nil {
+			bin = &Bin{Low: float64(idx) / float64(bins), High: float64(idx+1) / float64(bins)}
+			sums[observation.Estimator][idx] = bin
+		}
+		bin.Count++
+		bin.MeanClaimed += observation.Probability
+		if observation.Hit {
+			bin.Observed++
+		}
+	}
+
+	result := make([]*Curve, 0, len(curves))
+	for _, name := range sortedNames(curves) {
+		curve := curves[name]
+		for _, bin := range sums[name] {
+			if bin == nil {
+				continue
+			}
+
+			bin.MeanClaimed /= float64(bin.Count)
+			bin.Observed /= float64(bin.Count)
+			curve.ECE += float64(bin.Count) / float64(curve.Count) * math.Abs(bin.Observed-bin.MeanClaimed)
+			curve.Bins = append(curve.Bins, bin)
+		}
+		result = append(result, curve)
+	}
+
+	return result
+}
+
+// Target compares the claimed and observed frequency of the predictions of
+// an estimator with the same tier and window
+type Target struct {
+	Estimator string
+	Tier      string
+	Blocks    int64
+	Seconds   int64
+	Count     int //observations which are not censored
+	Censored  int
+	Claimed   float64 //mean claimed probability
+	Observed  float64 //frequency of the hits
+}
+
+type targetKey struct {
+	estimator string
+	tier      string
+	blocks    int64
+	seconds   int64
+}
+
+// ByTarget groups the observations by estimator, tier and window
+func ByTarget(observations []*Observation) []*Target {
+	targets := make(map[targetKey]*Target)
+	for _, observation := range observations {
+		key := targetKey{observation.Estimator, observation.Tier, observation.Blocks, observation.Seconds}
+		target, ok := targets[key]
+		if !ok {
+			target = &Target{Estimator: key.estimator, Tier: key.tier, Blocks: key.blocks, Seconds: key.seconds}
+			targets[key] = target
+		}
+		if observation.Censored {
+			target.Censored++
+			continue
+		}
+
+		target.Count++
+		target.Claimed += observation.Probability
+		if observation.Hit {
+			target.Observed++
+		}
+	}
+
+	result := make([]*Target, 0, len(targets))
+	for _, target := range targets {
+		if target.Count > 0 {
+			target.Claimed /= float64(target.Count)
+			target.Observed /= float64(target.Count)
+		}
+		result = append(result, target)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Estimator != b.Estimator {
+			return a.Estimator < b.Estimator
+		}
+		if a.Seconds != b.Seconds {
+			return a.Seconds < b.Seconds
+		}
+		if a.Blocks != b.Blocks {
+			return a.Blocks < b.Blocks
+		}
+		return a.Tier < b.Tier
+	})
+
+	return result
+}
+
+func sortedNames(curves map[string]*Curve) []string {
+	names := make([]string, 0, len(curves))
+	for name := range curves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
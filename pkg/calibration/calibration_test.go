@@ -0,0 +1,65 @@
+package calibration
+
+import (
+	"testing"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserve(t *testing.T) {
+	claim := &scoring.Claim{Probability: 0.98, Seconds: 60}
+
+	hit := Observe("web3j", "fast", claim, &scoring.Inclusion{Blocks: 2, Seconds: 24}, 10, 120)
+	late := Observe("web3j", "fast", claim, &scoring.Inclusion{Blocks: 8, Seconds: 96}, 10, 120)
+	miss := Observe("web3j", "fast", claim, nil, 10, 120)
+	censored := Observe("web3j", "slow", &scoring.Claim{Probability: 0.98, Seconds: 3600}, nil, 10, 120)
+
+	assert.True(t, hit.Hit)
+	assert.False(t, late.Hit || late.Censored)
+	assert.False(t, miss.Hit || miss.Censored)
+	assert.True(t, censored.Censored)
+}
+
+func TestObserveCensorsWindowsLongerThanTheHorizonWhateverTheOutcome(t *testing.T) {
+	// arrange
+	claim := &scoring.Claim{Probability: 0.98, Seconds: 3600}
+
+	// act
+	hit := Observe("web3j", "slow", claim, &scoring.Inclusion{Blocks: 2, Seconds: 24}, 10, 120)
+	miss := Observe("web3j", "slow", claim, nil, 10, 120)
+	curves := Reliability([]*Observation{hit, miss}, 10)
+
+	// assert
+	assert.True(t, hit.Censored)
+	assert.True(t, miss.Censored)
+	require.Len(t, curves, 1)
+	assert.Equal(t, 0, curves[0].Count)
+	assert.Equal(t, 2, curves[0].Censored)
+}
+
+func TestReliability(t *testing.T) {
+	// arrange
+	var observations []*Observation
+	for i := 0; i < 10; i++ {
+		//claims 90%, observes 50%
+		observations = append(observations, &Observation{Estimator: "express", Probability: 0.9, Hit: i%2 == 0})
+		//claims 35%, observes 30%
+		observations = append(observations, &Observation{Estimator: "express", Probability: 0.35, Hit: i < 3})
+	}
+	observations = append(observations, &Observation{Estimator: "express", Probability: 1, Censored: true})
+
+	// act
+	curves := Reliability(observations, 10)
+
+	// assert
+	require.Len(t, curves, 1)
+	curve := curves[0]
+	assert.Equal(t, 20, curve.Count)
+	assert.Equal(t, 1, curve.Censored)
+	require.Len(t, curve.Bins, 2)
+	assert.InDelta(t, 0.3, curve.Bins[0].Observed, 1e-9)
+	assert.InDelta(t, 0.5, curve.Bins[1].Observed, 1e-9)
+	assert.InDelta(t, 0.5*0.05+0.5*0.4, curve.ECE, 1e-9)
+}
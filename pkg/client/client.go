@@ -0,0 +1,245 @@
+// Package client fetches the estimates of a running estimator server (see
+// the serve command) over REST or Server-Sent Events.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned if the server has no estimate of the estimator
+	ErrNotFound = errors.New("no estimate of the estimator")
+
+	// ErrStale is returned if the latest estimate is older than Config.MaxAge
+	ErrStale = errors.New("estimate is stale")
+
+	// ErrUnknownTier is returned if the estimate has no such tier
+	ErrUnknownTier = errors.New("unknown tier")
+)
+
+// Defaults of the config
+const (
+	DefaultTimeout      = 5 * time.Second
+	DefaultRetries      = 2
+	DefaultRetryBackoff = 200 * time.Millisecond
+	DefaultCacheTTL     = 2 * time.Second
+	DefaultMaxAge       = 2 * time.Minute
+)
+
+// Config configures a client, zero values are replaced by the defaults
+type Config struct {
+	URL          string        //base url of the server, e.g. http://localhost:8080
+	HTTPClient   *http.Client  //Timeout is only applied to a client created by New
+	Timeout      time.Duration //per request
+	Retries      int           //retries of failed requests (network errors and 5xx)
+	RetryBackoff time.Duration //wait before the first retry, doubled per retry
+	CacheTTL     time.Duration //estimates are served from the cache for this long
+	MaxAge       time.Duration //estimates older than this are not used
+	Fallback     Fallback      //used by Price if no estimate can be used, may be nil
+}
+
+type cachedEstimate struct {
+	estimate  *Estimate
+	fetchedAt time.Time
+}
+
+// Client fetches the estimates of a server. Estimates are cached, if the
+// server cannot be reached a cached estimate is used as long as it is not
+// older than MaxAge. It is safe for concurrent use.
+type Client struct {
+	config Config
+	base   *url.URL
+	now    func() time.Time
+
+	cache map[string]*cachedEstimate
+	mu    sync.Mutex
+}
+
+// New creates a client for the server at config.URL
+func New(config Config) (*Client, error) {
+	base, err := url.Parse(strings.TrimSuffix(config.URL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme != "http" && base.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported url %q, expected http(s)://host:port", config.URL)
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: config.Timeout}
+	}
+	if config.Retries < 0 {
+		config.Retries = 0
+	} else if config.Retries == 0 {
+		config.Retries = DefaultRetries
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultRetryBackoff
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = DefaultCacheTTL
+	}
+	if config.MaxAge <= 0 {
+		config.MaxAge = DefaultMaxAge
+	}
+
+	return &Client{
+		config: config,
+		base:   base,
+		now:    time.Now,
+		cache:  make(map[string]*cachedEstimate),
+	}, nil
+}
+
+// Estimate returns the latest estimate of the estimator. It is served from
+// the cache if it was fetched within CacheTTL, otherwise it is fetched. If the
+// server fails the cached estimate is returned unless it is stale.
+func (c *Client) Estimate(ctx context.Context, estimator string) (*Estimate, error) {
+	c.mu.Lock()
+	cached, ok := c.cache[estimator]
+	c.mu.Unlock()
+	if ok && c.now().Sub(cached.fetchedAt) < c.config.CacheTTL && !c.stale(cached) {
+		return cached.estimate, nil
+	}
+
+	estimate := new(Estimate)
+	err := c.get(ctx, "/v1/estimates/"+url.PathEscape(estimator), estimate)
+	if err != nil {
+		if ok && err != ErrNotFound && !c.stale(cached) {
+			return cached.estimate, nil
+		}
+		return nil, err
+	}
+
+	fetched := c.store(estimate)
+	if c.stale(fetched) {
+		return nil, fmt.Errorf("%w: %v is %.0fs old", ErrStale, estimator, c.age(fetched).Seconds())
+	}
+
+	return estimate, nil
+}
+
+// Estimates fetches the latest estimates of all estimators
+func (c *Client) Estimates(ctx context.Context) ([]*Estimate, error) {
+	var response estimatesResponse
+	err := c.get(ctx, "/v1/estimates", &response)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, estimate := range response.Estimates {
+		c.store(estimate)
+	}
+	return response.Estimates, nil
+}
+
+// Price returns the price of the tier of the estimator in wei. If there is
+// no usable estimate the price of the fallback is returned.
+func (c *Client) Price(ctx context.Context, estimator string, tier string) (int64, error) {
+	estimate, err := c.Estimate(ctx, estimator)
+	if err == nil {
+		price, ok := estimate.Price(tier)
+		if !ok {
+			return 0, fmt.Errorf("%w %v of %v", ErrUnknownTier, tier, estimator)
+		}
+		return price, nil
+	}
+
+	if c.config.Fallback == nil {
+		return 0, err
+	}
+
+	price, fallbackErr := c.config.Fallback.Price(ctx, tier)
+	if fallbackErr != nil {
+		return 0, fmt.Errorf("%v, fallback failed: %v", err, fallbackErr)
+	}
+	return price, nil
+}
+
+// store caches the estimate
+func (c *Client) store(estimate *Estimate) *cachedEstimate {
+	cached := &cachedEstimate{estimate: estimate, fetchedAt: c.now()}
+
+	c.mu.Lock()
+	c.cache[estimate.Estimator] = cached
+	c.mu.Unlock()
+	return cached
+}
+
+// age returns the age of the cached estimate
+func (c *Client) age(cached *cachedEstimate) time.Duration {
+	served := time.Duration(cached.estimate.AgeSeconds * float64(time.Second))
+	return served + c.now().Sub(cached.fetchedAt)
+}
+
+// stale reports whether the cached estimate is older than MaxAge
+func (c *Client) stale(cached *cachedEstimate) bool {
+	return c.age(cached) > c.config.MaxAge
+}
+
+// get fetches path into out, retrying network errors and server errors
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	backoff := c.config.RetryBackoff
+	var err error
+	for attempt := 0; attempt <= c.config.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var retry bool
+		retry, err = c.fetch(ctx, path, out)
+		if !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+// fetch requests path once, it reports whether a failure may be retried
+func (c *Client) fetch(ctx context.Context, path string, out interface{}) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base.String()+path, nil)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := c.config.HTTPClient.Do(request)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return true, err
+	}
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+		return false, json.Unmarshal(body, out)
+	case response.StatusCode == http.StatusNotFound:
+		return false, ErrNotFound
+	default:
+		var message errorResponse
+		json.Unmarshal(body, &message)
+		return response.StatusCode >= 500, fmt.Errorf("server responded with %v: %v", response.Status, message.Error)
+	}
+}
@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/server"
+)
+
+// flakyServer serves the handler of a snapshot, failing while down is set
+func flakyServer(t *testing.T, snapshot *server.Snapshot, down *int32, requests *int32) *httptest.Server {
+	handler := server.NewHandler(snapshot, zap.NewNop())
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if atomic.LoadInt32(down) == 1 {
+			http.Error(w, `{"error":"down"}`, http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// pastClock returns the time an hour ago until it is reset
+type pastClock struct {
+	offset time.Duration
+}
+
+func (c *pastClock) Now() time.Time {
+	return time.Now().Add(-c.offset)
+}
+
+func update(snapshot *server.Snapshot, block int64, fast int64) {
+	snapshot.Update(server.Estimate{
+		Estimator:   "naive",
+		BlockNumber: block,
+		Tiers:       []string{"standard", "fast"},
+		Prices:      map[string]int64{"standard": fast / 2, "fast": fast},
+	})
+}
+
+func TestClientRetriesAndServesCachedEstimates(t *testing.T) {
+	// arrange
+	snapshot := server.NewSnapshot(nil)
+	update(snapshot, 100, 2000000000)
+	var down, requests int32
+	ts := flakyServer(t, snapshot, &down, &requests)
+	client, err := New(Config{URL: ts.URL, Retries: 2, RetryBackoff: time.Millisecond, CacheTTL: time.Hour})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// act
+	estimate, err := client.Estimate(ctx, "naive")
+	require.NoError(t, err)
+	cached, cachedErr := client.Estimate(ctx, "naive")
+	_, tierErr := client.Price(ctx, "naive", "slow")
+	_, unknownErr := client.Estimate(ctx, "web3j")
+	cachedRequests := atomic.LoadInt32(&requests)
+
+	client.config.CacheTTL = time.Nanosecond
+	atomic.StoreInt32(&down, 1)
+	fallback, fallbackErr := client.Price(ctx, "naive", "fast")
+	failedRequests := atomic.LoadInt32(&requests) - cachedRequests
+
+	// assert
+	assert.Equal(t, int64(100), estimate.BlockNumber)
+	price, ok := estimate.Price("fast")
+	assert.True(t, ok)
+	assert.Equal(t, int64(2000000000), price)
+	assert.NoError(t, cachedErr)
+	assert.Equal(t, estimate, cached)
+	assert.Equal(t, int32(2), cachedRequests)
+	assert.NoError(t, fallbackErr)
+	assert.Equal(t, int64(2000000000), fallback)
+	assert.Equal(t, int32(3), failedRequests)
+	assert.ErrorIs(t, unknownErr, ErrNotFound)
+	assert.ErrorIs(t, tierErr, ErrUnknownTier)
+}
+
+func TestClientFallsBackOnStaleEstimates(t *testing.T) {
+	// arrange
+	clock := &pastClock{offset: time.Hour}
+	snapshot := server.NewSnapshot(clock)
+	update(snapshot, 100, 2000000000)
+	clock.offset = 0
+	var down, requests int32
+	ts := flakyServer(t, snapshot, &down, &requests)
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x3b9aca00"}`))
+	}))
+	defer node.Close()
+
+	stale, err := New(Config{URL: ts.URL, MaxAge: time.Minute, Fallback: &Node{URL: node.URL}})
+	require.NoError(t, err)
+	missing, err := New(Config{URL: ts.URL, Fallback: &Static{Wei: 7}})
+	require.NoError(t, err)
+
+	// act
+	_, staleErr := stale.Estimate(context.Background(), "naive")
+	nodePrice, nodeErr := stale.Price(context.Background(), "naive", "fast")
+	staticPrice, staticErr := missing.Price(context.Background(), "web3j", "fast")
+
+	// assert
+	assert.ErrorIs(t, staleErr, ErrStale)
+	assert.NoError(t, nodeErr)
+	assert.Equal(t, int64(1000000000), nodePrice)
+	assert.NoError(t, staticErr)
+	assert.Equal(t, int64(7), staticPrice)
+}
+
+func TestClientWatchesEstimates(t *testing.T) {
+	// arrange
+	snapshot := server.NewSnapshot(nil)
+	update(snapshot, 100, 2000000000)
+	var down, requests int32
+	ts := flakyServer(t, snapshot, &down, &requests)
+	client, err := New(Config{URL: ts.URL, CacheTTL: time.Hour})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// act
+	estimates := client.Watch(ctx, Filter{Estimators: []string{"naive"}})
+	current := <-estimates
+	update(snapshot, 101, 3000000000)
+	next := <-estimates
+	cached, cachedErr := client.Estimate(ctx, "naive")
+	cancel()
+	_, open := <-estimates
+
+	// assert
+	assert.Equal(t, int64(100), current.BlockNumber)
+	assert.Equal(t, int64(101), next.BlockNumber)
+	require.Len(t, next.Tiers, 2)
+	assert.Equal(t, int64(3000000000), next.Tiers[1].Wei)
+	assert.NoError(t, cachedErr)
+	assert.Equal(t, next, cached)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	assert.False(t, open)
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// Fallback provides a price if the server has no usable estimate
+type Fallback interface {
+	Price(ctx context.Context, tier string) (int64, error)
+}
+
+// Static is a fallback with the same price for every tier
+type Static struct {
+	Wei int64
+}
+
+// Price returns the static price
+func (s *Static) Price(ctx context.Context, tier string) (int64, error) {
+	return s.Wei, nil
+}
+
+// Node is a fallback asking a node for eth_gasPrice, for every tier
+type Node struct {
+	URL        string
+	HTTPClient *http.Client //http.DefaultClient if nil
+}
+
+type nodeResponse struct {
+	Result string `json:"result"`
+	Error  *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Price returns the gas price of the node
+func (n *Node) Price(ctx context.Context, tier string) (int64, error) {
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_gasPrice","params":[]}`)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	httpClient := n.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+
+	var result nodeResponse
+	err = json.NewDecoder(response.Body).Decode(&result)
+	if err != nil {
+		return 0, fmt.Errorf("invalid eth_gasPrice response: %v", err)
+	}
+	if result.Error != nil {
+		return 0, fmt.Errorf("eth_gasPrice failed: %v", result.Error.Message)
+	}
+
+	price, ok := new(big.Int).SetString(strings.TrimPrefix(result.Result, "0x"), 16)
+	if !ok || !price.IsInt64() {
+		return 0, fmt.Errorf("invalid gas price %q", result.Result)
+	}
+	return price.Int64(), nil
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Tier is the price of a tier of an estimate
+type Tier struct {
+	Name string  `json:"name"`
+	Wei  int64   `json:"wei"`
+	Gwei float64 `json:"gwei"`
+}
+
+// Estimate is an estimate served by GET /v1/estimates/{estimator}
+type Estimate struct {
+	Estimator   string            `json:"estimator"`
+	BlockNumber int64             `json:"blockNumber"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	AgeSeconds  float64           `json:"ageSeconds"` //age when it was served
+	Tiers       []Tier            `json:"tiers"`
+	Parameters  map[string]string `json:"parameters"`
+}
+
+// Price returns the price of the tier in wei, false if the estimate has no
+// such tier
+func (e *Estimate) Price(tier string) (int64, bool) {
+	for _, t := range e.Tiers {
+		if t.Name == tier {
+			return t.Wei, true
+		}
+	}
+
+	return 0, false
+}
+
+type estimatesResponse struct {
+	Estimates []*Estimate `json:"estimates"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Filter selects the estimators and tiers of a watch, empty lists select all
+type Filter struct {
+	Estimators []string
+	Tiers      []string
+}
+
+// query returns the filter as query parameters
+func (f Filter) query() string {
+	values := url.Values{}
+	if len(f.Estimators) > 0 {
+		values.Set("estimator", strings.Join(f.Estimators, ","))
+	}
+	if len(f.Tiers) > 0 {
+		values.Set("tier", strings.Join(f.Tiers, ","))
+	}
+
+	if len(values) == 0 {
+		return ""
+	}
+	return "?" + values.Encode()
+}
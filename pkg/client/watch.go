@@ -0,0 +1,105 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// maxReconnectBackoff limits the wait between reconnects of a watch
+const maxReconnectBackoff = 30 * time.Second
+
+// Watch streams the estimates selected by the filter from /v1/stream, starting
+// with the current ones. It reconnects with backoff if the stream fails and,
+// unless the filter selects tiers, keeps the cache up to date. The channel is
+// closed once ctx is done.
+func (c *Client) Watch(ctx context.Context, filter Filter) <-chan *Estimate {
+	estimates := make(chan *Estimate)
+	go func() {
+		defer close(estimates)
+
+		backoff := c.config.RetryBackoff
+		for {
+			received, _ := c.stream(ctx, filter, estimates)
+			if received {
+				backoff = c.config.RetryBackoff
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+			if backoff > maxReconnectBackoff {
+				backoff = maxReconnectBackoff
+			}
+		}
+	}()
+
+	return estimates
+}
+
+// stream reads one event stream until it fails, it reports whether any
+// estimate was received
+func (c *Client) stream(ctx context.Context, filter Filter, estimates chan<- *Estimate) (bool, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.base.String()+"/v1/stream"+filter.query(), nil)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Accept", "text/event-stream")
+
+	//the stream is long-lived, so the timeout of the configured client does not apply
+	client := *c.config.HTTPClient
+	client.Timeout = 0
+	response, err := client.Do(request)
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("server responded with %v", response.Status)
+	}
+
+	received := false
+	event, data := "", ""
+	scanner := bufio.NewScanner(response.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if event == "estimate" && data != "" {
+				estimate := new(Estimate)
+				err = json.Unmarshal([]byte(data), estimate)
+				if err != nil {
+					return received, err
+				}
+				if len(filter.Tiers) == 0 {
+					c.store(estimate)
+				}
+
+				select {
+				case <-ctx.Done():
+					return received, ctx.Err()
+				case estimates <- estimate:
+				}
+				received = true
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+
+	if scanner.Err() != nil {
+		return received, scanner.Err()
+	}
+	return received, fmt.Errorf("event stream closed")
+}
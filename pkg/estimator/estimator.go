@@ -0,0 +1,274 @@
+// Package estimator embeds the estimators in other programs. An estimator is
+// created on an injected block source, started under a context and its latest
+// result can be read concurrently:
+//
+//	e, err := estimator.New(estimator.Naive, blocks, estimator.WithLogger(logger))
+//	err = e.Start(ctx)
+//	result, ok := e.Latest()
+//	err = e.Stop()
+package estimator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/gasstation/express"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/naive"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/web3j"
+
+	"go.uber.org/zap"
+)
+
+// Names of the estimators
+const (
+	Naive   = "naive"
+	Express = "express"
+	Web3j   = "web3j"
+)
+
+var (
+	// Names are the estimators which can be created by New
+	Names = []string{Naive, Express, Web3j}
+
+	// ErrStarted is returned by Start if the estimator is already running
+	ErrStarted = errors.New("estimator is already started")
+)
+
+// stepper estimates once for the latest block, see backtest.Estimator
+type stepper interface {
+	Step() error
+}
+
+// Result is the latest prediction of an estimator
+type Result struct {
+	Estimator   string
+	BlockNumber int64
+	Tiers       []string
+	Prices      map[string]int64          //tier -> price in wei
+	Claims      map[string]*scoring.Claim //tier -> claimed inclusion, if any
+	UpdatedAt   time.Time
+}
+
+// Estimator runs one of the estimators on a block source. It holds no global
+// state, so any number of estimators can run in the same process.
+type Estimator struct {
+	name    string
+	tiers   []string
+	options *options
+	stepper stepper
+
+	stepMu sync.Mutex //serializes the steps of the loop and Step
+
+	mu      sync.RWMutex
+	latest  *Result
+	lastErr error
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// New creates the named estimator (see Names) loading the blocks from blocks
+func New(name string, blocks utils.BlockSource, opts ...Option) (*Estimator, error) {
+	if blocks == nil {
+		return nil, errors.New("a block source is required")
+	}
+
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.logger == nil {
+		o.logger = zap.NewNop()
+	}
+	if o.interval <= 0 {
+		o.interval = DefaultInterval
+	}
+	if o.clock == nil {
+		o.clock = utils.SystemClock
+	}
+	if o.classifier == nil {
+		WithChainID(1)(o)
+	}
+
+	e := &Estimator{name: name, options: o}
+	r := &recorder{e: e}
+	logger := o.logger.With(zap.String("estimator", name))
+	switch name {
+	case Naive:
+		e.tiers = naive.Tiers
+		e.stepper = naive.NewEstimator(logger, o.naive, blocks, o.classifier, r)
+	case Express:
+		e.tiers = express.Tiers
+		e.stepper = express.NewEstimator(logger, o.express, blocks, o.classifier, r)
+	case Web3j:
+		err := web3j.ValidateTiers(o.tiers)
+		if err != nil {
+			return nil, err
+		}
+
+		builders := o.builders
+		if builders == nil {
+			builders, err = utils.NewBuilderRegistry(utils.DefaultBuilderRules)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		e.tiers = web3j.TierNames(o.tiers)
+		e.stepper = web3j.NewEstimator(logger, blocks, o.classifier, o.tiers, builders, r)
+	default:
+		return nil, fmt.Errorf("unknown estimator %v, supported are %v", name, Names)
+	}
+
+	return e, nil
+}
+
+// Name returns the name of the estimator
+func (e *Estimator) Name() string {
+	return e.name
+}
+
+// Tiers returns the tiers predicted by the estimator
+func (e *Estimator) Tiers() []string {
+	return append([]string(nil), e.tiers...)
+}
+
+// Start estimates for the latest block every interval until ctx is done or
+// Stop is called. A failed step does not stop the estimator, its error is
+// reported by Err until a step succeeds.
+func (e *Estimator) Start(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.done != nil {
+		return ErrStarted
+	}
+
+	ctx, e.cancel = context.WithCancel(ctx)
+	e.done = make(chan struct{})
+	go e.loop(ctx, e.done)
+	return nil
+}
+
+// Run starts the estimator and blocks until ctx is done, it returns the error
+// of the last step
+func (e *Estimator) Run(ctx context.Context) error {
+	err := e.Start(ctx)
+	if err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+	return e.Stop()
+}
+
+// Stop stops a started estimator and waits for its running step to finish. It
+// returns the error of the last step.
+func (e *Estimator) Stop() error {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.cancel, e.done = nil, nil
+	e.mu.Unlock()
+
+	if done != nil {
+		cancel()
+		<-done
+	}
+	return e.Err()
+}
+
+// Step estimates once for the latest block, independent of the loop
+func (e *Estimator) Step() error {
+	e.stepMu.Lock()
+	defer e.stepMu.Unlock()
+
+	err := e.stepper.Step()
+	e.mu.Lock()
+	e.lastErr = err
+	e.mu.Unlock()
+	return err
+}
+
+// Latest returns the latest result, false if the estimator has not predicted
+// yet. The result must not be modified.
+func (e *Estimator) Latest() (*Result, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.latest, e.latest != nil
+}
+
+// Err returns the error of the last step, nil if it succeeded
+func (e *Estimator) Err() error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.lastErr
+}
+
+// Unwrap returns the underlying estimator, e.g. a *web3j.Estimator to query
+// its probability curves
+func (e *Estimator) Unwrap() interface{} {
+	return e.stepper
+}
+
+func (e *Estimator) loop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(e.options.interval)
+	defer ticker.Stop()
+	for {
+		err := e.Step()
+		if err != nil {
+			e.options.logger.Error("estimation failed", zap.String("estimator", e.name), zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// recorder is the scoring.Recorder of the underlying estimator, it keeps the
+// predictions as the latest result and passes them on to the recorder of the
+// options
+type recorder struct {
+	e *Estimator
+}
+
+func (r *recorder) AddPrediction(prediction *scoring.Prediction) {
+	e := r.e
+	result := &Result{
+		Estimator:   e.name,
+		BlockNumber: prediction.BlockNumber,
+		Tiers:       e.tiers,
+		Prices:      make(map[string]int64, len(prediction.Prices)),
+		Claims:      make(map[string]*scoring.Claim, len(prediction.Claims)),
+		UpdatedAt:   e.options.clock.Now(),
+	}
+	for tier, price := range prediction.Prices {
+		result.Prices[tier] = price
+	}
+	for tier, claim := range prediction.Claims {
+		copied := *claim
+		result.Claims[tier] = &copied
+	}
+
+	e.mu.Lock()
+	e.latest = result
+	e.mu.Unlock()
+
+	if e.options.recorder != nil {
+		e.options.recorder.AddPrediction(prediction)
+	}
+}
+
+func (r *recorder) PredictScores() error {
+	if r.e.options.recorder == nil {
+		return nil
+	}
+	return r.e.options.recorder.PredictScores()
+}
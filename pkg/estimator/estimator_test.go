@@ -0,0 +1,132 @@
+package estimator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// emptyChain serves empty blocks up to a settable head
+type emptyChain struct {
+	head int64
+	err  error
+	mu   sync.Mutex
+}
+
+func (c *emptyChain) setHead(head int64) {
+	c.mu.Lock()
+	c.head = head
+	c.mu.Unlock()
+}
+
+func (c *emptyChain) GetLastestBlock() (*utils.Block, error) {
+	c.mu.Lock()
+	head, err := c.head, c.err
+	c.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return c.GetBlockByNumber(big.NewInt(head))
+}
+
+func (c *emptyChain) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	return &utils.Block{
+		Hash:   common.BigToHash(blockNumber),
+		Number: (*hexutil.Big)(new(big.Int).Set(blockNumber)),
+	}, nil
+}
+
+func (c *emptyChain) GetBlockByHash(hash common.Hash) (*utils.Block, error) {
+	return c.GetBlockByNumber(new(big.Int).SetBytes(hash[:]))
+}
+
+// predictions counts the predictions passed on to the recorder
+type predictions struct {
+	count int
+	mu    sync.Mutex
+}
+
+func (p *predictions) AddPrediction(prediction *scoring.Prediction) {
+	p.mu.Lock()
+	p.count++
+	p.mu.Unlock()
+}
+
+func (p *predictions) PredictScores() error {
+	return nil
+}
+
+func TestEstimatorRunsUntilStopped(t *testing.T) {
+	// arrange
+	chain := &emptyChain{head: 10}
+	recorder := &predictions{}
+	e, err := New(Naive, chain, WithInterval(time.Millisecond), WithRecorder(recorder))
+	require.NoError(t, err)
+	latest := func() int64 {
+		result, ok := e.Latest()
+		if !ok {
+			return 0
+		}
+		return result.BlockNumber
+	}
+
+	// act
+	_, before := e.Latest()
+	require.NoError(t, e.Start(context.Background()))
+	startedErr := e.Start(context.Background())
+	require.Eventually(t, func() bool { return latest() == 10 }, time.Second, time.Millisecond)
+	chain.setHead(11)
+	require.Eventually(t, func() bool { return latest() == 11 }, time.Second, time.Millisecond)
+	stopErr := e.Stop()
+	chain.setHead(12)
+	time.Sleep(10 * time.Millisecond)
+
+	// assert
+	assert.False(t, before)
+	assert.Equal(t, ErrStarted, startedErr)
+	assert.NoError(t, stopErr)
+	result, ok := e.Latest()
+	require.True(t, ok)
+	assert.Equal(t, int64(11), result.BlockNumber)
+	assert.Equal(t, []string{"standard"}, result.Tiers)
+	assert.Equal(t, int64(1e9), result.Prices["standard"])
+	recorder.mu.Lock()
+	assert.Equal(t, 2, recorder.count)
+	recorder.mu.Unlock()
+}
+
+func TestEstimatorReportsErrors(t *testing.T) {
+	// arrange
+	chain := &emptyChain{head: 10, err: errors.New("node unavailable")}
+	e, err := New(Naive, chain, WithInterval(time.Millisecond))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// act
+	runErr := make(chan error)
+	go func() {
+		runErr <- e.Run(ctx)
+	}()
+	require.Eventually(t, func() bool { return e.Err() != nil }, time.Second, time.Millisecond)
+	cancel()
+	_, unknownErr := New("unknown", chain)
+	_, sourceErr := New(Naive, nil)
+
+	// assert
+	assert.EqualError(t, <-runErr, "node unavailable")
+	_, ok := e.Latest()
+	assert.False(t, ok)
+	assert.Error(t, unknownErr)
+	assert.Error(t, sourceErr)
+}
@@ -0,0 +1,117 @@
+package estimator
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/gasstation/express"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/naive"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/web3j"
+
+	"go.uber.org/zap"
+)
+
+// DefaultInterval is the default interval the latest block is checked in
+const DefaultInterval = 10 * time.Second
+
+// Option configures an estimator created by New
+type Option func(*options)
+
+type options struct {
+	logger     *zap.Logger
+	interval   time.Duration
+	clock      utils.Clock
+	classifier *utils.TxClassifier
+	recorder   scoring.Recorder
+
+	naive    naive.Config
+	express  express.Config
+	tiers    []web3j.Tier
+	builders *utils.BuilderRegistry
+}
+
+func defaultOptions() *options {
+	return &options{
+		logger:   zap.NewNop(),
+		interval: DefaultInterval,
+		clock:    utils.SystemClock,
+		naive:    naive.DefaultConfig,
+		express:  express.DefaultConfig,
+		tiers:    web3j.DefaultTiers,
+	}
+}
+
+// WithLogger sets the logger, nothing is logged by default
+func WithLogger(logger *zap.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithInterval sets the interval the latest block is checked in
+func WithInterval(interval time.Duration) Option {
+	return func(o *options) {
+		o.interval = interval
+	}
+}
+
+// WithClock sets the clock the results are timestamped with
+func WithClock(clock utils.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithChainID sets the chain id the transaction senders are recovered with,
+// mainnet by default
+func WithChainID(chainID int64) Option {
+	return func(o *options) {
+		o.classifier = utils.NewTxClassifier(big.NewInt(chainID))
+	}
+}
+
+// WithClassifier sets the classifier of the transactions, it replaces
+// WithChainID
+func WithClassifier(classifier *utils.TxClassifier) Option {
+	return func(o *options) {
+		o.classifier = classifier
+	}
+}
+
+// WithRecorder passes every prediction on to the recorder, e.g. scores
+func WithRecorder(recorder scoring.Recorder) Option {
+	return func(o *options) {
+		o.recorder = recorder
+	}
+}
+
+// WithNaiveConfig configures the naive estimator
+func WithNaiveConfig(config naive.Config) Option {
+	return func(o *options) {
+		o.naive = config
+	}
+}
+
+// WithExpressConfig configures the express estimator
+func WithExpressConfig(config express.Config) Option {
+	return func(o *options) {
+		o.express = config
+	}
+}
+
+// WithTiers sets the tiers of the web3j estimator
+func WithTiers(tiers []web3j.Tier) Option {
+	return func(o *options) {
+		o.tiers = tiers
+	}
+}
+
+// WithBuilders sets the builder registry of the web3j estimator, the default
+// rules are used otherwise
+func WithBuilders(builders *utils.BuilderRegistry) Option {
+	return func(o *options) {
+		o.builders = builders
+	}
+}
@@ -0,0 +1,5 @@
+// Package feeestimatorpb contains the gRPC API of the fee estimator and its
+// generated Go client (NewFeeEstimatorClient).
+package feeestimatorpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative feeestimator.proto
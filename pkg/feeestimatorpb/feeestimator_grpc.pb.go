@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: feeestimator.proto
+
+package feeestimatorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FeeEstimator_GetEstimate_FullMethodName         = "/feeestimator.v1.FeeEstimator/GetEstimate"
+	FeeEstimator_WatchEstimates_FullMethodName      = "/feeestimator.v1.FeeEstimator/WatchEstimates"
+	FeeEstimator_GetProbabilityCurve_FullMethodName = "/feeestimator.v1.FeeEstimator/GetProbabilityCurve"
+)
+
+// FeeEstimatorClient is the client API for FeeEstimator service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FeeEstimator serves the estimates of the running estimators.
+type FeeEstimatorClient interface {
+	// GetEstimate returns the latest estimate of an estimator, limited to the
+	// requested tiers (all if none are requested).
+	GetEstimate(ctx context.Context, in *GetEstimateRequest, opts ...grpc.CallOption) (*Estimate, error)
+	// WatchEstimates streams the current estimates and every new one. A slow
+	// client skips superseded estimates and receives the latest per estimator.
+	WatchEstimates(ctx context.Context, in *WatchEstimatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Estimate], error)
+	// GetProbabilityCurve returns the probability of a transaction to be mined
+	// within max_wait_seconds per gas price, derived by the web3j estimator.
+	GetProbabilityCurve(ctx context.Context, in *GetProbabilityCurveRequest, opts ...grpc.CallOption) (*ProbabilityCurve, error)
+}
+
+type feeEstimatorClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFeeEstimatorClient(cc grpc.ClientConnInterface) FeeEstimatorClient {
+	return &feeEstimatorClient{cc}
+}
+
+func (c *feeEstimatorClient) GetEstimate(ctx context.Context, in *GetEstimateRequest, opts ...grpc.CallOption) (*Estimate, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Estimate)
+	err := c.cc.Invoke(ctx, FeeEstimator_GetEstimate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *feeEstimatorClient) WatchEstimates(ctx context.Context, in *WatchEstimatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Estimate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FeeEstimator_ServiceDesc.Streams[0], FeeEstimator_WatchEstimates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEstimatesRequest, Estimate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FeeEstimator_WatchEstimatesClient = grpc.ServerStreamingClient[Estimate]
+
+func (c *feeEstimatorClient) GetProbabilityCurve(ctx context.Context, in *GetProbabilityCurveRequest, opts ...grpc.CallOption) (*ProbabilityCurve, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProbabilityCurve)
+	err := c.cc.Invoke(ctx, FeeEstimator_GetProbabilityCurve_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FeeEstimatorServer is the server API for FeeEstimator service.
+// All implementations must embed UnimplementedFeeEstimatorServer
+// for forward compatibility.
+//
+// FeeEstimator serves the estimates of the running estimators.
+type FeeEstimatorServer interface {
+	// GetEstimate returns the latest estimate of an estimator, limited to the
+	// requested tiers (all if none are requested).
+	GetEstimate(context.Context, *GetEstimateRequest) (*Estimate, error)
+	// WatchEstimates streams the current estimates and every new one. A slow
+	// client skips superseded estimates and receives the latest per estimator.
+	WatchEstimates(*WatchEstimatesRequest, grpc.ServerStreamingServer[Estimate]) error
+	// GetProbabilityCurve returns the probability of a transaction to be mined
+	// within max_wait_seconds per gas price, derived by the web3j estimator.
+	GetProbabilityCurve(context.Context, *GetProbabilityCurveRequest) (*ProbabilityCurve, error)
+	mustEmbedUnimplementedFeeEstimatorServer()
+}
+
+// UnimplementedFeeEstimatorServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFeeEstimatorServer struct{}
+
+func (UnimplementedFeeEstimatorServer) GetEstimate(context.Context, *GetEstimateRequest) (*Estimate, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEstimate not implemented")
+}
+func (UnimplementedFeeEstimatorServer) WatchEstimates(*WatchEstimatesRequest, grpc.ServerStreamingServer[Estimate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEstimates not implemented")
+}
+func (UnimplementedFeeEstimatorServer) GetProbabilityCurve(context.Context, *GetProbabilityCurveRequest) (*ProbabilityCurve, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProbabilityCurve not implemented")
+}
+func (UnimplementedFeeEstimatorServer) mustEmbedUnimplementedFeeEstimatorServer() {}
+func (UnimplementedFeeEstimatorServer) testEmbeddedByValue()                      {}
+
+// UnsafeFeeEstimatorServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FeeEstimatorServer will
+// result in compilation errors.
+type UnsafeFeeEstimatorServer interface {
+	mustEmbedUnimplementedFeeEstimatorServer()
+}
+
+func RegisterFeeEstimatorServer(s grpc.ServiceRegistrar, srv FeeEstimatorServer) {
+	// If the following call pancis, it indicates UnimplementedFeeEstimatorServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FeeEstimator_ServiceDesc, srv)
+}
+
+func _FeeEstimator_GetEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEstimateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeeEstimatorServer).GetEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeeEstimator_GetEstimate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeeEstimatorServer).GetEstimate(ctx, req.(*GetEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FeeEstimator_WatchEstimates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEstimatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FeeEstimatorServer).WatchEstimates(m, &grpc.GenericServerStream[WatchEstimatesRequest, Estimate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FeeEstimator_WatchEstimatesServer = grpc.ServerStreamingServer[Estimate]
+
+func _FeeEstimator_GetProbabilityCurve_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProbabilityCurveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FeeEstimatorServer).GetProbabilityCurve(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FeeEstimator_GetProbabilityCurve_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FeeEstimatorServer).GetProbabilityCurve(ctx, req.(*GetProbabilityCurveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FeeEstimator_ServiceDesc is the grpc.ServiceDesc for FeeEstimator service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FeeEstimator_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "feeestimator.v1.FeeEstimator",
+	HandlerType: (*FeeEstimatorServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetEstimate",
+			Handler:    _FeeEstimator_GetEstimate_Handler,
+		},
+		{
+			MethodName: "GetProbabilityCurve",
+			Handler:    _FeeEstimator_GetProbabilityCurve_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEstimates",
+			Handler:       _FeeEstimator_WatchEstimates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "feeestimator.proto",
+}
@@ -7,3 +7,17 @@ const (
 	Standard = 60
 	Fast     = 90
 )
+
+// Config contains the % of the recent blocks that have to accept the
+// recommended gas prices
+type Config struct {
+	SafeLow  uint64
+	Standard uint64
+	Fast     uint64
+}
+
+// DefaultConfig uses the thresholds of the gas station
+var DefaultConfig = Config{SafeLow: SafeLow, Standard: Standard, Fast: Fast}
+
+// Tiers predicted by the estimator, slow is the SafeLow price
+var Tiers = []string{"slow", "standard", "fast", "fastest"}
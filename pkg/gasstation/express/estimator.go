@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
 	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
 
 	. "github.com/ahmetb/go-linq"
@@ -25,21 +26,25 @@ var (
 type Estimator struct {
 	cleanBlocks             map[string]*CleanBlock
 	logger                  *zap.Logger
-	rpcClient               *utils.CachedRPCClient
+	config                  Config
+	blocks                  utils.BlockSource
+	classifier              *utils.TxClassifier
 	lastObservedBlockNumber uint64
 
 	mutex  *sync.Mutex
-	scores *scores
+	scores scoring.Recorder
 }
 
 // NewEstimator returns a new express estimator
-func NewEstimator(logger *zap.Logger, rpcClient *utils.CachedRPCClient) *Estimator {
+func NewEstimator(logger *zap.Logger, config Config, blocks utils.BlockSource, classifier *utils.TxClassifier, scores scoring.Recorder) *Estimator {
 	return &Estimator{
-		rpcClient:   rpcClient,
+		config:      config,
+		blocks:      blocks,
+		classifier:  classifier,
 		logger:      logger,
 		cleanBlocks: make(map[string]*CleanBlock),
 		mutex:       &sync.Mutex{},
-		scores:      newScores(rpcClient, logger),
+		scores:      scores,
 	}
 }
 
@@ -73,12 +78,18 @@ func (e *Estimator) Run() error {
 	return <-errorChannel
 }
 
+// Step estimates the fees once for the latest block of the block source, Run
+// does so on every tick
+func (e *Estimator) Step() error {
+	return e.doWork()
+}
+
 func (e *Estimator) doWork() error {
 	e.mutex.Lock() //prevents duplicate loading if operation needs longer than tick
 	defer e.mutex.Unlock()
 
 	//get current block number
-	latestBlock, err := e.rpcClient.GetLastestBlock()
+	latestBlock, err := e.blocks.GetLastestBlock()
 	if err != nil {
 		return err
 	}
@@ -125,16 +136,28 @@ func (e *Estimator) estimateFees() error {
 	}
 
 	table := makePredictionTable(hp)
-	predictions := getGaspriceRecs(table, e.lastObservedBlockNumber, blockTime)
+	predictions := getGaspriceRecs(table, e.config, e.lastObservedBlockNumber, blockTime)
 	e.logger.Info("estimation complete: ", zap.Any("predictions", predictions), zap.Any("standardGwei", predictions.Standard/utils.GWei))
 
-	e.scores.addPrediction(predictions.SafeLow, predictions.Standard, predictions.Fast, predictions.Fastest, int64(predictions.BlockNumber))
-	return e.scores.predictScores()
+	prediction := scoring.NewPrediction(int64(predictions.BlockNumber), map[string]int64{
+		"slow":     int64(predictions.SafeLow),
+		"standard": int64(predictions.Standard),
+		"fast":     int64(predictions.Fast),
+		"fastest":  int64(predictions.Fastest),
+	})
+
+	//the share of recent blocks accepting a price is taken as the probability
+	//that the next block accepts it
+	prediction.Claims["slow"] = &scoring.Claim{Probability: float64(e.config.SafeLow) / 100, Blocks: 1}
+	prediction.Claims["standard"] = &scoring.Claim{Probability: float64(e.config.Standard) / 100, Blocks: 1}
+	prediction.Claims["fast"] = &scoring.Claim{Probability: float64(e.config.Fast) / 100, Blocks: 1}
+	e.scores.AddPrediction(prediction)
+	return e.scores.PredictScores()
 }
 
 func (e *Estimator) processBlockTxs(blockNumber *big.Int) (*CleanBlock, error) {
 	//TODO this returns invalid blocks --> GP = 0 find out why
-	block, err := e.rpcClient.GetBlockByNumber(blockNumber)
+	block, err := e.blocks.GetBlockByNumber(blockNumber)
 	if err != nil {
 		return nil, err
 	}
@@ -142,8 +165,7 @@ func (e *Estimator) processBlockTxs(blockNumber *big.Int) (*CleanBlock, error) {
 	sort.Sort(utils.TransactionsByGasPrice(block.Transactions))
 	cleanBlock := newCleanBlock(block)
 	for _, tx := range block.Transactions {
-		if tx.GasPrice().Uint64() == 0 { //It sometimes happens that a whole block has gp = 0
-			e.logger.Warn("gas price was 0", zap.Uint64("number", block.Number.ToInt().Uint64()))
+		if !e.classifier.IsMarketTx(block, tx) { //zero priced and miner self-payments
 			continue
 		}
 
@@ -318,12 +340,12 @@ func getHashpowerAccepting(price uint64, hp hashpower) uint64 {
 	return hpa
 }
 
-func getGaspriceRecs(table *predictionTable, blockNumber uint64, blockTime int64) *gasPricePredictions {
+func getGaspriceRecs(table *predictionTable, config Config, blockNumber uint64, blockTime int64) *gasPricePredictions {
 	predictions := &gasPricePredictions{}
 
 	var lowPrices []uint64
 	From(table.predictions).WhereT(func(prediction *pricePrediction) bool {
-		return prediction.HashpowerAccepting >= SafeLow
+		return prediction.HashpowerAccepting >= config.SafeLow
 	}).SelectT(func(prediction *pricePrediction) uint64 {
 		return prediction.GasPrice
 	}).ToSlice(&lowPrices)
@@ -331,7 +353,7 @@ func getGaspriceRecs(table *predictionTable, blockNumber uint64, blockTime int64
 
 	var avgPrices []uint64
 	From(table.predictions).WhereT(func(prediction *pricePrediction) bool {
-		return prediction.HashpowerAccepting >= Standard
+		return prediction.HashpowerAccepting >= config.Standard
 	}).SelectT(func(prediction *pricePrediction) uint64 {
 		return prediction.GasPrice
 	}).ToSlice(&avgPrices)
@@ -339,7 +361,7 @@ func getGaspriceRecs(table *predictionTable, blockNumber uint64, blockTime int64
 
 	var fastPrices []uint64
 	From(table.predictions).WhereT(func(prediction *pricePrediction) bool {
-		return prediction.HashpowerAccepting >= Fast
+		return prediction.HashpowerAccepting >= config.Fast
 	}).SelectT(func(prediction *pricePrediction) uint64 {
 		return prediction.GasPrice
 	}).ToSlice(&fastPrices)
@@ -1,15 +1,15 @@
 package naive
 
 import (
-	"errors"
 	"math/big"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
 	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
 
-	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 
@@ -19,30 +19,69 @@ import (
 var (
 	//RefreshInterval for fee estimation
 	RefreshInterval = 10 * time.Second
+
+	//Tiers predicted by the estimator
+	Tiers = []string{"standard"}
 )
 
 // Estimator implements a naive gas price estimation
 type Estimator struct {
-	logger   *zap.Logger
-	config   gasprice.Config
-	maxEmpty int
+	logger *zap.Logger
+	config Config
 
 	lastObserved *big.Int
 	mutex        *sync.Mutex
-	scores       *scores
-	rpcClient    *utils.CachedRPCClient
+	scores       scoring.Recorder
+	blocks       utils.BlockSource
+	classifier   *utils.TxClassifier
+
+	cacheLock *sync.RWMutex
+	lastHead  common.Hash
+	lastPrice *big.Int
 }
 
-// NewEstimator creates a new estimation.Estimator
-func NewEstimator(logger *zap.Logger, config gasprice.Config, rpcClient *utils.CachedRPCClient) *Estimator {
+// NewEstimator creates a new estimation.Estimator. Invalid config values are
+// replaced by the defaults of the geth oracle.
+func NewEstimator(logger *zap.Logger, config Config, blocks utils.BlockSource, classifier *utils.TxClassifier, scores scoring.Recorder) *Estimator {
+	if config.Blocks < 1 {
+		config.Blocks = 1
+		logger.Warn("sanitizing invalid gasprice oracle sample blocks", zap.Int("updated", config.Blocks))
+	}
+	if config.Percentile < 0 || config.Percentile > 100 {
+		config.Percentile = 60
+		logger.Warn("sanitizing invalid gasprice oracle percentile", zap.Int("updated", config.Percentile))
+	}
+	if config.Samples < 1 {
+		config.Samples = DefaultSamples
+		logger.Warn("sanitizing invalid gasprice oracle samples per block", zap.Int("updated", config.Samples))
+	}
+	if config.MaxPrice == nil || config.MaxPrice.Sign() <= 0 {
+		config.MaxPrice = new(big.Int).Set(utils.MaxPrice)
+		logger.Warn("sanitizing invalid gasprice oracle price cap", zap.Stringer("updated", config.MaxPrice))
+	}
+	if config.IgnorePrice == nil || config.IgnorePrice.Sign() <= 0 {
+		config.IgnorePrice = new(big.Int).Set(gasprice.DefaultIgnorePrice)
+		logger.Warn("sanitizing invalid gasprice oracle ignore price", zap.Stringer("updated", config.IgnorePrice))
+	}
+	if config.Default == nil || config.Default.Sign() <= 0 {
+		config.Default = big.NewInt(utils.GWei)
+		logger.Warn("sanitizing invalid gasprice oracle default price", zap.Stringer("updated", config.Default))
+	}
+	if config.Default.Cmp(config.MaxPrice) > 0 {
+		config.Default = new(big.Int).Set(config.MaxPrice)
+		logger.Warn("capping gasprice oracle default price", zap.Stringer("updated", config.Default))
+	}
+
 	return &Estimator{
 		logger:       logger,
 		config:       config,
-		maxEmpty:     config.Blocks / 2,
 		mutex:        &sync.Mutex{},
 		lastObserved: big.NewInt(-1),
-		rpcClient:    rpcClient,
-		scores:       newScores(rpcClient, logger),
+		blocks:       blocks,
+		classifier:   classifier,
+		scores:       scores,
+		cacheLock:    &sync.RWMutex{},
+		lastPrice:    config.Default,
 	}
 }
 
@@ -71,11 +110,17 @@ func (e *Estimator) Run() error {
 	return <-errorChannel
 }
 
+// Step estimates the fees once for the latest block of the block source, Run
+// does so on every tick
+func (e *Estimator) Step() error {
+	return e.estimateFees()
+}
+
 func (e *Estimator) estimateFees() error {
 	e.mutex.Lock() //prevents duplicate loading if operation needs longer than tick
 	defer e.mutex.Unlock()
 
-	latest, err := e.rpcClient.GetLastestBlock()
+	latest, err := e.blocks.GetLastestBlock()
 	if err != nil {
 		return err
 	}
@@ -90,89 +135,136 @@ func (e *Estimator) estimateFees() error {
 		e.logger.Error("an error occurred while suggesting gas price", zap.Error(err))
 		return err
 	}
-	e.logger.Info("estimation complete: ", zap.Any("gasPriceGwei", prediction.Price.Uint64()/utils.GWei), zap.Any("prediction", prediction))
-	e.scores.addPrediction(prediction.Price.Int64(), latest.Number.ToInt().Int64())
-	return e.scores.predictScores()
+	e.logger.Info("estimation complete: ", zap.Any("gasPriceGwei", prediction.Price.Uint64()/utils.GWei), zap.Any("tipGwei", prediction.Tip.Uint64()/utils.GWei), zap.Any("prediction", prediction))
+	e.scores.AddPrediction(scoring.NewPrediction(latest.Number.ToInt().Int64(), map[string]int64{
+		"standard": prediction.Price.Int64(),
+	}))
+	return e.scores.PredictScores()
 }
 
-// SuggestGasPrice suggests a gas price in gwei
+// SuggestGasPrice suggests a gas price in wei. Like eth_gasPrice of geth it
+// samples the cheapest transactions of the last blocks, takes the configured
+// percentile of their effective tips and adds the base fee of the head. The
+// tip is cached per head block.
 func (e *Estimator) SuggestGasPrice() (*GasPricePrediction, error) {
-	header, err := e.rpcClient.GetLastestBlock()
+	header, err := e.blocks.GetLastestBlock()
 	if err != nil {
 		return nil, err
 	}
 
 	currentBlockNumber := header.Number.ToInt()
+	e.cacheLock.RLock()
+	lastHead, lastPrice := e.lastHead, e.lastPrice
+	e.cacheLock.RUnlock()
+	if header.Hash == lastHead {
+		return newGasPricePrediction(header, lastPrice), nil
+	}
+
 	checkBlocks := e.config.Blocks
-	ch := make(chan getBlockPricesResult, checkBlocks)
+	ch := make(chan getBlockPricesResult, checkBlocks*2) //at most 2*checkBlocks are loaded
 	sent := 0
 	exp := 0
 
-	var blockPrices []*big.Int
+	var results []*big.Int
 	blockNum := currentBlockNumber.Uint64()
-	signer := types.NewEIP155Signer(nil)
-
 	for sent < checkBlocks && blockNum > 0 {
-		big := hexutil.Big(*big.NewInt(int64(blockNum)))
-		go e.getBlockPrices(signer, &big, ch)
+		go e.getBlockValues(blockNum, ch)
 		sent++
 		exp++
-		blockNum-- //TODO possibly skip a block
+		blockNum--
 	}
 
-	maxEmpty := e.maxEmpty
 	for exp > 0 {
 		res := <-ch
 		if res.err != nil {
 			return nil, res.err
 		}
 		exp--
-		if res.price != nil {
-			blockPrices = append(blockPrices, res.price)
-			continue
+
+		// Nothing returned. There are two special cases here:
+		// - The block is empty
+		// - All the transactions included are sent by the miner itself.
+		// In these cases, use the latest calculated price for sampling.
+		if len(res.prices) == 0 {
+			res.prices = []*big.Int{lastPrice}
 		}
-		if maxEmpty > 0 {
-			maxEmpty--
-			continue
+
+		// Besides, in order to collect enough data for sampling, if nothing
+		// meaningful returned, try to query more blocks. But the maximum
+		// is 2*checkBlocks.
+		if len(res.prices) == 1 && len(results)+1+exp < checkBlocks*2 && blockNum > 0 {
+			go e.getBlockValues(blockNum, ch)
+			sent++
+			exp++
+			blockNum--
 		}
+		results = append(results, res.prices...)
+	}
 
-		//TODO handle failed --> possibly reload or ignore as it is in gasPriceOracle
+	price := lastPrice
+	if len(results) > 0 {
+		sort.Sort(bigIntArray(results))
+		price = results[(len(results)-1)*e.config.Percentile/100]
+	}
+	if price.Cmp(e.config.MaxPrice) > 0 {
+		price = new(big.Int).Set(e.config.MaxPrice)
 	}
 
-	prediction := &GasPricePrediction{BlockNumber: currentBlockNumber}
-	if len(blockPrices) > 0 {
-		sort.Sort(bigIntArray(blockPrices))
-		prediction.Price = blockPrices[(len(blockPrices)-1)*e.config.Percentile/100]
+	e.cacheLock.Lock()
+	e.lastHead = header.Hash
+	e.lastPrice = price
+	e.cacheLock.Unlock()
 
-		if prediction.Price.Cmp(utils.MaxPrice) > 0 {
-			prediction.Price = new(big.Int).Set(utils.MaxPrice)
-		}
+	e.lastObserved = currentBlockNumber
+	return newGasPricePrediction(header, price), nil
+}
 
-		e.lastObserved = currentBlockNumber
-		return prediction, err
+// newGasPricePrediction adds the base fee of the head to the tip, the gas
+// price equals the tip before london
+func newGasPricePrediction(head *utils.Block, tip *big.Int) *GasPricePrediction {
+	price := new(big.Int).Set(tip)
+	if head.BaseFee != nil {
+		price.Add(price, head.BaseFee.ToInt())
 	}
 
-	return nil, errors.New("not enough blocks")
+	return &GasPricePrediction{Price: price, Tip: new(big.Int).Set(tip), BlockNumber: head.Number.ToInt()}
 }
 
-// getBlockPrices calculates the lowest transaction gas price in a given block
-// and sends it to the result channel. If the block is empty price is nil.
-// If the block is incomplete or an error occurred the error is sent to the channel.
-func (e *Estimator) getBlockPrices(signer types.Signer, blockNum *hexutil.Big, ch chan getBlockPricesResult) {
-	block, err := e.rpcClient.GetBlockByNumber(blockNum.ToInt())
+// getBlockValues calculates the lowest effective tips of a given block and
+// sends them to the result channel. Transactions that are not priced by the
+// fee market (see utils.TxClassifier) and tips below the ignore price are
+// skipped, at most config.Samples prices are returned. If the block is empty
+// the prices are nil. If an error occurred the error is sent to the channel.
+func (e *Estimator) getBlockValues(blockNum uint64, ch chan getBlockPricesResult) {
+	block, err := e.blocks.GetBlockByNumber(new(big.Int).SetUint64(blockNum))
 	if err != nil {
 		ch <- getBlockPricesResult{nil, nil, err}
 		return
 	}
 
-	sort.Sort(utils.TransactionsByGasPrice(block.Transactions))
+	var baseFee *big.Int
+	if block.BaseFee != nil {
+		baseFee = block.BaseFee.ToInt()
+	}
+
+	//sort a copy since blocks are shared through the cache
+	txs := make([]*types.Transaction, len(block.Transactions))
+	copy(txs, block.Transactions)
+	sort.Sort(&transactionsByGasTip{txs: txs, baseFee: baseFee})
 
-	for _, tx := range block.Transactions {
-		sender, err := types.Sender(signer, tx)
-		if err == nil && sender != block.Miner {
-			ch <- getBlockPricesResult{tx.GasPrice(), blockNum.ToInt(), nil}
-			return
+	var prices []*big.Int
+	for _, tx := range txs {
+		tip, _ := tx.EffectiveGasTip(baseFee)
+		if tip.Cmp(e.config.IgnorePrice) < 0 {
+			continue
+		}
+
+		if e.classifier.IsMarketTx(block, tx) {
+			prices = append(prices, tip)
+			if len(prices) >= e.config.Samples {
+				break
+			}
 		}
 	}
-	ch <- getBlockPricesResult{nil, nil, nil}
+	ch <- getBlockPricesResult{prices, block.Number.ToInt(), nil}
 }
@@ -0,0 +1,92 @@
+package naive
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// chain serves the blocks by number, the last one is the head
+type chain struct {
+	blocks []*utils.Block
+}
+
+func (c *chain) GetLastestBlock() (*utils.Block, error) {
+	return c.blocks[len(c.blocks)-1], nil
+}
+
+func (c *chain) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	for _, block := range c.blocks {
+		if block.Number.ToInt().Cmp(blockNumber) == 0 {
+			return block, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (c *chain) GetBlockByHash(hash common.Hash) (*utils.Block, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestSuggestGasPriceAddsTheBaseFeeToTheTip(t *testing.T) {
+	// arrange
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	miner := common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	sign := func(chainID int64, nonce uint64, tipGwei int64) *types.Transaction {
+		tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(chainID)), &types.DynamicFeeTx{
+			ChainID:   big.NewInt(chainID),
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(tipGwei * utils.GWei),
+			GasFeeCap: big.NewInt(100 * utils.GWei),
+			Gas:       21000,
+			To:        &to,
+			Value:     big.NewInt(0),
+		})
+		require.NoError(t, err)
+		return tx
+	}
+	block := func(number int64, txs ...*types.Transaction) *utils.Block {
+		return &utils.Block{
+			Hash:         common.Hash{byte(number)},
+			Miner:        miner,
+			Number:       (*hexutil.Big)(big.NewInt(number)),
+			BaseFee:      (*hexutil.Big)(big.NewInt(10 * utils.GWei)),
+			Transactions: txs,
+		}
+	}
+	blocks := &chain{blocks: []*utils.Block{
+		block(1, sign(5, 0, 1), sign(1, 1, 2)), //signed for another chain, the sender is not recoverable
+		block(2, sign(1, 2, 3)),
+		block(3, sign(1, 3, 4)),
+	}}
+
+	config := DefaultConfig
+	config.Blocks = 3
+	config.Percentile = 0
+	config.Samples = 1
+	estimator := NewEstimator(zap.NewNop(), config, blocks, utils.NewTxClassifier(big.NewInt(1)), nil)
+
+	// act
+	prediction, err := estimator.SuggestGasPrice()
+	cached, cachedErr := estimator.SuggestGasPrice()
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2*utils.GWei), prediction.Tip)
+	assert.Equal(t, big.NewInt(12*utils.GWei), prediction.Price)
+	assert.Equal(t, big.NewInt(3), prediction.BlockNumber)
+	require.NoError(t, cachedErr)
+	assert.Equal(t, prediction, cached)
+}
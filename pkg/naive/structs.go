@@ -2,10 +2,35 @@ package naive
 
 import (
 	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/gasprice"
 )
 
+// DefaultSamples is the number of cheapest transactions sampled per block
+const DefaultSamples = 3
+
+// Config extends the geth oracle config with the number of transactions
+// sampled per block
+type Config struct {
+	gasprice.Config
+	Samples int
+}
+
+// DefaultConfig samples the 60th percentile of the last 20 blocks
+var DefaultConfig = Config{
+	Config: gasprice.Config{
+		Blocks:      20,
+		Percentile:  60,
+		Default:     big.NewInt(1e9),
+		MaxPrice:    big.NewInt(500 * 1e9),
+		IgnorePrice: gasprice.DefaultIgnorePrice,
+	},
+	Samples: DefaultSamples,
+}
+
 type getBlockPricesResult struct {
-	price       *big.Int
+	prices      []*big.Int
 	blockNumber *big.Int
 	err         error
 }
@@ -16,7 +41,25 @@ func (s bigIntArray) Len() int           { return len(s) }
 func (s bigIntArray) Less(i, j int) bool { return s[i].Cmp(s[j]) < 0 }
 func (s bigIntArray) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
 
+// transactionsByGasTip sorts transactions by their effective tip, which
+// is the gas price for legacy transactions
+type transactionsByGasTip struct {
+	txs     []*types.Transaction
+	baseFee *big.Int
+}
+
+func (s *transactionsByGasTip) Len() int      { return len(s.txs) }
+func (s *transactionsByGasTip) Swap(i, j int) { s.txs[i], s.txs[j] = s.txs[j], s.txs[i] }
+func (s *transactionsByGasTip) Less(i, j int) bool {
+	// It's okay to discard the error because a tx would never be
+	// accepted into a block with an invalid effective tip.
+	tip1, _ := s.txs[i].EffectiveGasTip(s.baseFee)
+	tip2, _ := s.txs[j].EffectiveGasTip(s.baseFee)
+	return tip1.Cmp(tip2) < 0
+}
+
 type GasPricePrediction struct {
-	Price       *big.Int
+	Price       *big.Int //tip plus the base fee of the head
+	Tip         *big.Int
 	BlockNumber *big.Int
 }
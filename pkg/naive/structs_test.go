@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
 )
 
 func TestSorting(t *testing.T) {
@@ -23,7 +24,7 @@ func TestSorting(t *testing.T) {
 	txs := []*types.Transaction{tx, tx1, tx2}
 
 	// act
-	sort.Sort(transactionsByGasPrice(txs))
+	sort.Sort(utils.TransactionsByGasPrice(txs))
 
 	// assert
 	require.Len(t, txs, 3)
@@ -31,3 +32,21 @@ func TestSorting(t *testing.T) {
 	assert.Equal(t, txs[1].GasPrice(), standardGP)
 	assert.Equal(t, txs[2].GasPrice(), fastGP)
 }
+
+func TestSortingByEffectiveTip(t *testing.T) {
+	// arrange
+	baseFee := big.NewInt(10)
+	legacy := types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(14), nil)         //tip 4
+	capped := types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(5), GasFeeCap: big.NewInt(12)})    //tip 2
+	generous := types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(3), GasFeeCap: big.NewInt(100)}) //tip 3
+	txs := []*types.Transaction{legacy, capped, generous}
+
+	// act
+	sort.Sort(&transactionsByGasTip{txs: txs, baseFee: baseFee})
+
+	// assert
+	require.Len(t, txs, 3)
+	assert.Equal(t, capped, txs[0])
+	assert.Equal(t, generous, txs[1])
+	assert.Equal(t, legacy, txs[2])
+}
@@ -0,0 +1,150 @@
+// Package oracle polls the built-in fee oracles of nodes (eth_gasPrice,
+// eth_maxPriorityFeePerGas and eth_feeHistory) and records their values like
+// the predictions of an estimator, so the estimators can be scored against
+// them.
+package oracle
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Methods of the node oracles which are polled
+const (
+	MethodGasPrice       = "eth_gasPrice"
+	MethodMaxPriorityFee = "eth_maxPriorityFeePerGas"
+	MethodFeeHistory     = "eth_feeHistory"
+)
+
+var (
+	//Methods are the methods polled if an endpoint configures none
+	Methods = []string{MethodGasPrice, MethodMaxPriorityFee, MethodFeeHistory}
+
+	//DefaultFeeHistoryBlocks is the number of blocks requested by eth_feeHistory
+	DefaultFeeHistoryBlocks = 5
+
+	//DefaultPercentiles are the reward percentiles requested by eth_feeHistory
+	DefaultPercentiles = []float64{10, 50, 90}
+
+	//DefaultEndpoints poll the default node
+	DefaultEndpoints = []Endpoint{{Name: "node", URL: utils.NodeURL}}
+)
+
+// Endpoint describes a node whose oracle is polled. Its values are scored as
+// the estimator Name. If Local is set it answers instead of the node at URL.
+type Endpoint struct {
+	Name             string    `json:"name"`
+	URL              string    `json:"url"`
+	Methods          []string  `json:"methods"`
+	FeeHistoryBlocks int       `json:"feeHistoryBlocks"`
+	Percentiles      []float64 `json:"percentiles"`
+	Local            *Local    `json:"local"`
+}
+
+// withDefaults returns the endpoint with the unset fields defaulted
+func (e Endpoint) withDefaults() Endpoint {
+	if len(e.Methods) == 0 {
+		e.Methods = Methods
+	}
+	if e.FeeHistoryBlocks < 1 {
+		e.FeeHistoryBlocks = DefaultFeeHistoryBlocks
+	}
+	if len(e.Percentiles) == 0 {
+		e.Percentiles = DefaultPercentiles
+	}
+
+	return e
+}
+
+// polls reports whether the endpoint polls the method
+func (e Endpoint) polls(method string) bool {
+	for _, m := range e.Methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TierNames returns the tiers recorded for the endpoint: gasPrice,
+// maxPriorityFee and feeHistory<percentile> depending on its methods
+func TierNames(endpoint Endpoint) []string {
+	endpoint = endpoint.withDefaults()
+
+	var tiers []string
+	if endpoint.polls(MethodGasPrice) {
+		tiers = append(tiers, "gasPrice")
+	}
+	if endpoint.polls(MethodMaxPriorityFee) {
+		tiers = append(tiers, "maxPriorityFee")
+	}
+	if endpoint.polls(MethodFeeHistory) {
+		for _, percentile := range endpoint.Percentiles {
+			tiers = append(tiers, feeHistoryTier(percentile))
+		}
+	}
+
+	return tiers
+}
+
+// feeHistoryTier names the tier of a reward percentile, e.g. feeHistory50
+func feeHistoryTier(percentile float64) string {
+	return "feeHistory" + strconv.FormatFloat(percentile, 'f', -1, 64)
+}
+
+// LoadEndpoints reads a JSON array of endpoints from the given file
+func LoadEndpoints(path string) ([]Endpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []Endpoint
+	err = json.Unmarshal(data, &endpoints)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpoints, ValidateEndpoints(endpoints)
+}
+
+// ValidateEndpoints checks that the endpoints can be polled and are scored
+// under distinct names
+func ValidateEndpoints(endpoints []Endpoint) error {
+	if len(endpoints) == 0 {
+		return errors.New("at least one endpoint is required")
+	}
+
+	names := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		if endpoint.Name == "" {
+			return errors.New("endpoint name must not be empty")
+		}
+		if names[endpoint.Name] {
+			return fmt.Errorf("duplicate endpoint %q", endpoint.Name)
+		}
+		names[endpoint.Name] = true
+
+		if endpoint.URL == "" && endpoint.Local == nil {
+			return fmt.Errorf("endpoint %q: url or local is required", endpoint.Name)
+		}
+		for _, method := range endpoint.Methods {
+			if method != MethodGasPrice && method != MethodMaxPriorityFee && method != MethodFeeHistory {
+				return fmt.Errorf("endpoint %q: unsupported method %q", endpoint.Name, method)
+			}
+		}
+		for _, percentile := range endpoint.Percentiles {
+			if percentile < 0 || percentile > 100 {
+				return fmt.Errorf("endpoint %q: percentiles must be between 0 and 100", endpoint.Name)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package oracle
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ybbus/jsonrpc"
+)
+
+// Caller calls a JSON-RPC method and decodes its result into out, it is
+// implemented by jsonrpc.RPCClient and Local
+type Caller interface {
+	CallFor(out interface{}, method string, params ...interface{}) error
+}
+
+// FeeHistory is the result of eth_feeHistory. BaseFee has one entry more than
+// the requested blocks, the base fee of the next block.
+type FeeHistory struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+	Reward       [][]*hexutil.Big `json:"reward"` //per block, one entry per requested percentile
+}
+
+// Local answers the oracle methods with fixed values instead of a node, it
+// stands in for an endpoint in tests. Methods without a value fail.
+type Local struct {
+	GasPrice       *hexutil.Big `json:"gasPrice"`
+	MaxPriorityFee *hexutil.Big `json:"maxPriorityFee"`
+	FeeHistory     *FeeHistory  `json:"feeHistory"`
+}
+
+// CallFor answers method like a node would, the params are ignored
+func (l *Local) CallFor(out interface{}, method string, params ...interface{}) error {
+	var result interface{}
+	switch method {
+	case MethodGasPrice:
+		if l.GasPrice != nil {
+			result = l.GasPrice
+		}
+	case MethodMaxPriorityFee:
+		if l.MaxPriorityFee != nil {
+			result = l.MaxPriorityFee
+		}
+	case MethodFeeHistory:
+		if l.FeeHistory != nil {
+			result = l.FeeHistory
+		}
+	}
+	if result == nil {
+		return fmt.Errorf("local oracle does not answer %s", method)
+	}
+
+	//round trip through JSON to decode like a node response
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, out)
+}
+
+// Dial returns the caller of the endpoint, its Local stand-in if set
+func Dial(endpoint Endpoint) Caller {
+	if endpoint.Local != nil {
+		return endpoint.Local
+	}
+
+	return jsonrpc.NewClient(endpoint.URL)
+}
@@ -0,0 +1,182 @@
+package oracle
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"go.uber.org/zap"
+)
+
+var (
+	//RefreshInterval for polling the oracles
+	RefreshInterval = 10 * time.Second
+)
+
+// Oracle polls the fee oracle of an endpoint once per block and records its
+// values as a prediction, so they are scored like the estimators. Priority
+// fees are turned into gas prices by adding the base fee of the head block.
+type Oracle struct {
+	logger   *zap.Logger
+	endpoint Endpoint
+	caller   Caller
+
+	lastObserved *big.Int
+	mutex        *sync.Mutex
+	scores       scoring.Recorder
+	blocks       utils.BlockSource
+}
+
+// NewOracle creates an oracle polling the endpoint through caller
+func NewOracle(logger *zap.Logger, endpoint Endpoint, caller Caller, blocks utils.BlockSource, scores scoring.Recorder) *Oracle {
+	return &Oracle{
+		logger:       logger.With(zap.String("oracle", endpoint.Name)),
+		endpoint:     endpoint.withDefaults(),
+		caller:       caller,
+		lastObserved: big.NewInt(-1),
+		mutex:        &sync.Mutex{},
+		scores:       scores,
+		blocks:       blocks,
+	}
+}
+
+// Run starts the main event loop for polling the oracle
+func (o *Oracle) Run() error {
+	ticker := time.NewTicker(RefreshInterval)
+	defer ticker.Stop()
+
+	errorChannel := make(chan error)
+	go func() {
+		err := o.Step() //since ticker only ticks after interval
+		if err != nil {
+			errorChannel <- err
+		}
+		for {
+			select {
+			case <-ticker.C:
+				err = o.Step()
+				if err != nil {
+					errorChannel <- err
+				}
+			}
+		}
+	}()
+
+	return <-errorChannel
+}
+
+// Step polls the oracle once if a new block was mined since the last poll
+func (o *Oracle) Step() error {
+	o.mutex.Lock() //prevents duplicate polling if a call needs longer than tick
+	defer o.mutex.Unlock()
+
+	latest, err := o.blocks.GetLastestBlock()
+	if err != nil {
+		return err
+	}
+
+	if o.lastObserved.Cmp(latest.Number.ToInt()) >= 0 {
+		o.logger.Info("already polled")
+		return nil
+	}
+
+	prices, err := o.Query(latest)
+	if err != nil {
+		o.logger.Error("an error occurred while polling the oracle", zap.Error(err))
+		return err
+	}
+	o.lastObserved = latest.Number.ToInt()
+
+	o.logger.Info("oracle polled", zap.Any("prices", prices))
+	o.scores.AddPrediction(scoring.NewPrediction(latest.Number.ToInt().Int64(), prices))
+	return o.scores.PredictScores()
+}
+
+// Query calls the methods of the endpoint for the given head and returns the
+// gas prices in wei per tier (see TierNames). eth_feeHistory is pinned to the
+// head, eth_gasPrice and eth_maxPriorityFeePerGas take no block and are
+// answered at the head of the endpoint when called, which may already be
+// ahead of the given head.
+func (o *Oracle) Query(head *utils.Block) (map[string]int64, error) {
+	baseFee := new(big.Int)
+	if head.BaseFee != nil {
+		baseFee = head.BaseFee.ToInt()
+	}
+
+	prices := make(map[string]int64)
+	if o.endpoint.polls(MethodGasPrice) {
+		price := new(hexutil.Big)
+		err := o.caller.CallFor(price, MethodGasPrice)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", MethodGasPrice, err)
+		}
+		prices["gasPrice"] = price.ToInt().Int64()
+	}
+
+	if o.endpoint.polls(MethodMaxPriorityFee) {
+		tip := new(hexutil.Big)
+		err := o.caller.CallFor(tip, MethodMaxPriorityFee)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", MethodMaxPriorityFee, err)
+		}
+		prices["maxPriorityFee"] = new(big.Int).Add(baseFee, tip.ToInt()).Int64()
+	}
+
+	if o.endpoint.polls(MethodFeeHistory) {
+		history := new(FeeHistory)
+		err := o.caller.CallFor(history, MethodFeeHistory, hexutil.Uint64(o.endpoint.FeeHistoryBlocks), head.Number, o.endpoint.Percentiles)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", MethodFeeHistory, err)
+		}
+		if history.OldestBlock != nil {
+			newest := new(big.Int).Add(history.OldestBlock.ToInt(), big.NewInt(int64(len(history.GasUsedRatio)-1)))
+			if newest.Cmp(head.Number.ToInt()) != 0 {
+				return nil, fmt.Errorf("%s: the history ends at block %v instead of the head %v", MethodFeeHistory, newest, head.Number.ToInt())
+			}
+		}
+
+		for i, percentile := range o.endpoint.Percentiles {
+			tip := medianReward(history, i)
+			prices[feeHistoryTier(percentile)] = new(big.Int).Add(baseFee, tip).Int64()
+		}
+	}
+
+	return prices, nil
+}
+
+// medianReward returns the median of the rewards at the given percentile
+// index, the mean of the two middle rewards for an even count. Empty blocks
+// are skipped since they report a reward of zero.
+func medianReward(history *FeeHistory, index int) *big.Int {
+	var rewards []*big.Int
+	for block, reward := range history.Reward {
+		if block < len(history.GasUsedRatio) && history.GasUsedRatio[block] == 0 {
+			continue
+		}
+		if index < len(reward) && reward[index] != nil {
+			rewards = append(rewards, reward[index].ToInt())
+		}
+	}
+
+	if len(rewards) == 0 {
+		return new(big.Int)
+	}
+
+	sort.Slice(rewards, func(i, j int) bool {
+		return rewards[i].Cmp(rewards[j]) < 0
+	})
+	middle := len(rewards) / 2
+	if len(rewards)%2 == 1 {
+		return new(big.Int).Set(rewards[middle])
+	}
+
+	median := new(big.Int).Add(rewards[middle-1], rewards[middle])
+	return median.Rsh(median, 1)
+}
@@ -0,0 +1,132 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// headSource serves a single head block
+type headSource struct {
+	head *utils.Block
+}
+
+func (s *headSource) GetLastestBlock() (*utils.Block, error) {
+	return s.head, nil
+}
+
+func (s *headSource) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	return s.head, nil
+}
+
+func (s *headSource) GetBlockByHash(hash common.Hash) (*utils.Block, error) {
+	return s.head, nil
+}
+
+// recorder keeps the predictions without scoring them
+type recorder struct {
+	predictions []*scoring.Prediction
+}
+
+func (r *recorder) AddPrediction(prediction *scoring.Prediction) {
+	r.predictions = append(r.predictions, prediction)
+}
+
+func (r *recorder) PredictScores() error {
+	return nil
+}
+
+func gwei(n int64) *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(n * utils.GWei))
+}
+
+func TestOracleRecordsLocalValuesOncePerBlock(t *testing.T) {
+	// arrange
+	local := &Local{
+		GasPrice:       gwei(12),
+		MaxPriorityFee: gwei(2),
+		FeeHistory: &FeeHistory{
+			OldestBlock:  (*hexutil.Big)(big.NewInt(98)),
+			BaseFee:      []*hexutil.Big{gwei(10), gwei(10), gwei(10), gwei(11)},
+			GasUsedRatio: []float64{0.5, 0, 0.7},
+			Reward: [][]*hexutil.Big{
+				{gwei(1), gwei(3)},
+				{gwei(0), gwei(0)}, //empty block
+				{gwei(2), gwei(5)},
+			},
+		},
+	}
+	endpoint := Endpoint{Name: "local", Local: local, Percentiles: []float64{10, 50}}
+	source := &headSource{head: &utils.Block{Number: (*hexutil.Big)(big.NewInt(100)), BaseFee: gwei(10)}}
+	scores := &recorder{}
+	oracle := NewOracle(zap.NewNop(), endpoint, Dial(endpoint), source, scores)
+
+	// act
+	require.NoError(t, oracle.Step())
+	require.NoError(t, oracle.Step())
+
+	// assert
+	require.Len(t, scores.predictions, 1)
+	prediction := scores.predictions[0]
+	assert.Equal(t, int64(100), prediction.BlockNumber)
+	assert.Equal(t, map[string]int64{
+		"gasPrice":       12 * utils.GWei,
+		"maxPriorityFee": 12 * utils.GWei,
+		"feeHistory10":   11.5 * utils.GWei, //median of 1 and 2, empty block skipped
+		"feeHistory50":   14 * utils.GWei,   //median of 3 and 5
+	}, prediction.Prices)
+	assert.Equal(t, []string{"gasPrice", "maxPriorityFee", "feeHistory10", "feeHistory50"}, TierNames(endpoint))
+}
+
+func TestMedianReward(t *testing.T) {
+	// arrange
+	history := &FeeHistory{
+		GasUsedRatio: []float64{0.5, 0.6, 0, 0.7},
+		Reward:       [][]*hexutil.Big{{gwei(3)}, {gwei(1)}, {gwei(0)}, {gwei(2)}},
+	}
+
+	// act
+	odd := medianReward(history, 0)
+	history.GasUsedRatio[2] = 0.1
+	even := medianReward(history, 0)
+	missing := medianReward(history, 1)
+
+	// assert
+	assert.Equal(t, big.NewInt(2*utils.GWei), odd)
+	assert.Equal(t, big.NewInt(1.5*utils.GWei), even) //median of 0, 1, 2 and 3
+	assert.Equal(t, new(big.Int), missing)
+}
+
+func TestOracleRejectsHistoryOfAnotherHead(t *testing.T) {
+	// arrange
+	local := &Local{FeeHistory: &FeeHistory{
+		OldestBlock:  (*hexutil.Big)(big.NewInt(99)),
+		BaseFee:      []*hexutil.Big{gwei(10), gwei(10), gwei(10)},
+		GasUsedRatio: []float64{0.5, 0.5},
+		Reward:       [][]*hexutil.Big{{gwei(1)}, {gwei(2)}},
+	}}
+	endpoint := Endpoint{Name: "local", Local: local, Methods: []string{MethodFeeHistory}, Percentiles: []float64{10}}
+	oracle := NewOracle(zap.NewNop(), endpoint, Dial(endpoint), nil, &recorder{})
+
+	// act
+	_, err := oracle.Query(&utils.Block{Number: (*hexutil.Big)(big.NewInt(101))})
+
+	// assert
+	assert.EqualError(t, err, "eth_feeHistory: the history ends at block 100 instead of the head 101")
+}
+
+func TestValidateEndpoints(t *testing.T) {
+	assert.NoError(t, ValidateEndpoints(DefaultEndpoints))
+	assert.Error(t, ValidateEndpoints(nil))
+	assert.Error(t, ValidateEndpoints([]Endpoint{{Name: "a", URL: "http://a"}, {Name: "a", URL: "http://b"}}))
+	assert.Error(t, ValidateEndpoints([]Endpoint{{Name: "a"}}))
+	assert.Error(t, ValidateEndpoints([]Endpoint{{Name: "a", URL: "http://a", Methods: []string{"eth_call"}}}))
+}
@@ -0,0 +1,274 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The reader below decodes files following parquet.thrift and the encodings
+// of the parquet format specification. It deliberately shares no code with
+// the writer, so that both sides of the round trip are checked against the
+// specification instead of against each other.
+
+// thriftStruct maps field ids to values: int64, []byte, []interface{} or
+// thriftStruct
+type thriftStruct map[int16]interface{}
+
+type compactReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *compactReader) byte() byte {
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *compactReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		panic("invalid varint")
+	}
+	r.pos += n
+	return v
+}
+
+func (r *compactReader) zigzag() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *compactReader) value(t byte) interface{} {
+	switch t {
+	case 1, 2: //boolean true and false in field headers
+		return t == 1
+	case 3: //byte
+		return int64(int8(r.byte()))
+	case 4, 5, 6: //i16, i32, i64
+		return r.zigzag()
+	case 7: //double
+		v := math.Float64frombits(binary.LittleEndian.Uint64(r.data[r.pos:]))
+		r.pos += 8
+		return v
+	case 8: //binary
+		n := int(r.uvarint())
+		v := r.data[r.pos : r.pos+n]
+		r.pos += n
+		return v
+	case 9, 10: //list, set
+		header := r.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]interface{}, size)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case 12: //struct
+		return r.structure()
+	}
+	panic(fmt.Sprintf("unsupported thrift type %v", t))
+}
+
+func (r *compactReader) structure() thriftStruct {
+	fields := thriftStruct{}
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 { //stop
+			return fields
+		}
+
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+// decodeLevels decodes count definition levels of bit width 1 encoded with
+// the RLE/bit-packing hybrid
+func decodeLevels(data []byte, count int) []int {
+	r := &compactReader{data: data}
+	var levels []int
+	for len(levels) < count {
+		header := r.uvarint()
+		if header&1 == 0 { //RLE run, the value takes one byte for bit width 1
+			value := int(r.byte())
+			for i := uint64(0); i < header>>1; i++ {
+				levels = append(levels, value)
+			}
+			continue
+		}
+
+		for i := uint64(0); i < header>>1; i++ { //groups of 8 bit-packed values
+			b := r.byte()
+			for bit := 0; bit < 8; bit++ {
+				levels = append(levels, int(b>>bit&1))
+			}
+		}
+	}
+
+	return levels[:count]
+}
+
+type readColumn struct {
+	name      string
+	physical  int64
+	optional  bool
+	converted int64 //-1 if not set
+}
+
+// readFile decodes the schema and all rows of a flat parquet file
+func readFile(t *testing.T, data []byte) ([]readColumn, [][]interface{}) {
+	require.True(t, len(data) >= 12)
+	require.Equal(t, "PAR1", string(data[:4]))
+	require.Equal(t, "PAR1", string(data[len(data)-4:]))
+	footerLength := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	footer := &compactReader{data: data[len(data)-8-footerLength : len(data)-8]}
+	meta := footer.structure()
+	require.Equal(t, footerLength, footer.pos, "the footer is not fully consumed")
+
+	//FileMetaData: 1 version, 2 schema, 3 num_rows, 4 row_groups
+	schema := meta[2].([]interface{})
+	root := schema[0].(thriftStruct)
+	require.Equal(t, int64(len(schema)-1), root[5], "num_children of the root")
+	var columns []readColumn
+	for _, element := range schema[1:] {
+		//SchemaElement: 1 type, 3 repetition_type, 4 name, 6 converted_type
+		e := element.(thriftStruct)
+		column := readColumn{name: string(e[4].([]byte)), physical: e[1].(int64), optional: e[3] == int64(1), converted: -1}
+		if converted, ok := e[6]; ok {
+			column.converted = converted.(int64)
+		}
+		columns = append(columns, column)
+	}
+
+	var rows [][]interface{}
+	numRows := meta[3].(int64)
+	for _, g := range meta[4].([]interface{}) {
+		//RowGroup: 1 columns, 2 total_byte_size, 3 num_rows
+		group := g.(thriftStruct)
+		groupRows := make([][]interface{}, group[3].(int64))
+		for i := range groupRows {
+			groupRows[i] = make([]interface{}, len(columns))
+		}
+
+		var totalSize int64
+		for c, chunk := range group[1].([]interface{}) {
+			//ColumnChunk: 3 meta_data, ColumnMetaData: 4 codec, 5 num_values,
+			//6 total_uncompressed_size, 9 data_page_offset
+			columnMeta := chunk.(thriftStruct)[3].(thriftStruct)
+			require.Equal(t, int64(0), columnMeta[4], "uncompressed")
+			require.Equal(t, int64(len(groupRows)), columnMeta[5])
+			totalSize += columnMeta[6].(int64)
+
+			page := &compactReader{data: data, pos: int(columnMeta[9].(int64))}
+			//PageHeader: 1 type, 2 uncompressed_page_size, 3 compressed_page_size, 5 data_page_header
+			header := page.structure()
+			require.Equal(t, int64(0), header[1], "data page")
+			require.Equal(t, columnMeta[6].(int64), int64(page.pos)-columnMeta[9].(int64)+header[3].(int64))
+			//DataPageHeader: 1 num_values, 2 encoding
+			dataPage := header[5].(thriftStruct)
+			require.Equal(t, int64(len(groupRows)), dataPage[1])
+			require.Equal(t, int64(0), dataPage[2], "PLAIN encoding")
+			values := data[page.pos : page.pos+int(header[3].(int64))]
+
+			defined := make([]int, len(groupRows))
+			for i := range defined {
+				defined[i] = 1
+			}
+			if columns[c].optional {
+				length := int(binary.LittleEndian.Uint32(values))
+				defined = decodeLevels(values[4:4+length], len(groupRows))
+				values = values[4+length:]
+			}
+
+			for i := range groupRows {
+				if defined[i] == 0 {
+					continue
+				}
+				switch columns[c].physical {
+				case 2: //INT64
+					groupRows[i][c] = int64(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				case 5: //DOUBLE
+					groupRows[i][c] = math.Float64frombits(binary.LittleEndian.Uint64(values))
+					values = values[8:]
+				case 6: //BYTE_ARRAY
+					length := int(binary.LittleEndian.Uint32(values))
+					groupRows[i][c] = string(values[4 : 4+length])
+					values = values[4+length:]
+				default:
+					t.Fatalf("unexpected physical type %v", columns[c].physical)
+				}
+			}
+			require.Empty(t, values, "the page is not fully consumed")
+		}
+		require.Equal(t, totalSize, group[2], "total_byte_size")
+		rows = append(rows, groupRows...)
+	}
+	require.Equal(t, numRows, int64(len(rows)))
+
+	return columns, rows
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	// arrange
+	groups := [][][]interface{}{
+		{{int64(1), "fast", 1.5}, {int64(-2), "", nil}},
+		{{int64(3), "standard", nil}, {int64(4), "slow", nil}, {int64(1 << 40), "ünïcode", -0.25}},
+	}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, testColumns)
+	require.NoError(t, err)
+
+	// act
+	for _, rows := range groups {
+		require.NoError(t, w.WriteRows(rows))
+	}
+	require.NoError(t, w.Close())
+	columns, rows := readFile(t, buf.Bytes())
+
+	// assert
+	assert.Equal(t, []readColumn{
+		{name: "block_number", physical: 2, converted: -1},
+		{name: "tier", physical: 6, converted: 0}, //UTF8
+		{name: "overpayment_percent", physical: 5, optional: true, converted: -1},
+	}, columns)
+	assert.Equal(t, append(groups[0], groups[1]...), rows)
+}
+
+func TestWriterRoundTripManyLevels(t *testing.T) {
+	// arrange
+	var written [][]interface{}
+	for i := 0; i < 300; i++ {
+		var value interface{}
+		if i%3 != 0 && i < 200 {
+			value = float64(i)
+		}
+		written = append(written, []interface{}{int64(i), "tier", value})
+	}
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, testColumns)
+	require.NoError(t, err)
+
+	// act
+	require.NoError(t, w.WriteRows(written))
+	require.NoError(t, w.Close())
+	_, rows := readFile(t, buf.Bytes())
+
+	// assert
+	assert.Equal(t, written, rows)
+}
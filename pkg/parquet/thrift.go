@@ -0,0 +1,120 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// thrift compact protocol types
+const (
+	typeI32    = 5
+	typeI64    = 6
+	typeBinary = 8
+	typeList   = 9
+	typeStruct = 12
+)
+
+// compactWriter encodes the parquet metadata with the thrift compact protocol
+type compactWriter struct {
+	buf         bytes.Buffer
+	lastFieldID []int16 //stack of the last field id per nested struct
+}
+
+func newCompactWriter() *compactWriter {
+	return &compactWriter{lastFieldID: []int16{0}}
+}
+
+func (c *compactWriter) Bytes() []byte {
+	return c.buf.Bytes()
+}
+
+func (c *compactWriter) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], v)
+	c.buf.Write(b[:n])
+}
+
+func (c *compactWriter) zigzag32(v int32) {
+	c.varint(uint64(uint32((v << 1) ^ (v >> 31))))
+}
+
+func (c *compactWriter) zigzag64(v int64) {
+	c.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (c *compactWriter) fieldHeader(id int16, fieldType byte) {
+	last := c.lastFieldID[len(c.lastFieldID)-1]
+	if delta := id - last; delta > 0 && delta <= 15 {
+		c.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		c.buf.WriteByte(fieldType)
+		c.zigzag32(int32(id))
+	}
+	c.lastFieldID[len(c.lastFieldID)-1] = id
+}
+
+func (c *compactWriter) I32(id int16, v int32) {
+	c.fieldHeader(id, typeI32)
+	c.zigzag32(v)
+}
+
+func (c *compactWriter) I64(id int16, v int64) {
+	c.fieldHeader(id, typeI64)
+	c.zigzag64(v)
+}
+
+func (c *compactWriter) String(id int16, v string) {
+	c.fieldHeader(id, typeBinary)
+	c.binary(v)
+}
+
+func (c *compactWriter) binary(v string) {
+	c.varint(uint64(len(v)))
+	c.buf.WriteString(v)
+}
+
+// Struct writes a nested struct, fields are written by fn
+func (c *compactWriter) Struct(id int16, fn func()) {
+	c.fieldHeader(id, typeStruct)
+	c.structBody(fn)
+}
+
+func (c *compactWriter) structBody(fn func()) {
+	c.lastFieldID = append(c.lastFieldID, 0)
+	fn()
+	c.buf.WriteByte(0) //stop
+	c.lastFieldID = c.lastFieldID[:len(c.lastFieldID)-1]
+}
+
+func (c *compactWriter) listHeader(id int16, size int, elemType byte) {
+	c.fieldHeader(id, typeList)
+	if size < 15 {
+		c.buf.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		c.buf.WriteByte(0xf0 | elemType)
+		c.varint(uint64(size))
+	}
+}
+
+// StructList writes a list of n structs, the fields of the i'th struct are
+// written by fn(i)
+func (c *compactWriter) StructList(id int16, n int, fn func(i int)) {
+	c.listHeader(id, n, typeStruct)
+	for i := 0; i < n; i++ {
+		c.structBody(func() { fn(i) })
+	}
+}
+
+func (c *compactWriter) I32List(id int16, values []int32) {
+	c.listHeader(id, len(values), typeI32)
+	for _, v := range values {
+		c.zigzag32(v)
+	}
+}
+
+func (c *compactWriter) StringList(id int16, values []string) {
+	c.listHeader(id, len(values), typeBinary)
+	for _, v := range values {
+		c.binary(v)
+	}
+}
@@ -0,0 +1,369 @@
+// Package parquet implements a minimal writer for flat parquet files with
+// uncompressed, PLAIN encoded INT64, DOUBLE and UTF8 columns.
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// Type of a column
+type Type int
+
+// Supported column types
+const (
+	Int64 Type = iota
+	Double
+	String
+)
+
+// parquet enums
+const (
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUTF8 = 0
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+
+	codecUncompressed = 0
+)
+
+var magic = []byte("PAR1")
+
+// ErrClosed is returned when writing to a closed writer
+var ErrClosed = errors.New("parquet writer is closed")
+
+// Column describes a column of the schema. Optional columns accept nil values.
+type Column struct {
+	Name     string
+	Type     Type
+	Optional bool
+}
+
+type columnChunk struct {
+	offset           int64
+	numValues        int64
+	uncompressedSize int64
+}
+
+type rowGroup struct {
+	numRows   int64
+	totalSize int64
+	chunks    []columnChunk
+}
+
+// Writer writes rows to a parquet file. Every call to WriteRows appends a
+// row group, the file metadata is written by Flush or Close.
+type Writer struct {
+	w         io.Writer
+	columns   []Column
+	offset    int64
+	footer    int64 //size of the footer written by Flush, overwritten by the next row group
+	numRows   int64
+	rowGroups []rowGroup
+	closed    bool
+}
+
+// NewWriter creates a writer for the given schema and writes the file header
+func NewWriter(w io.Writer, columns []Column) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, errors.New("schema has no columns")
+	}
+
+	pw := &Writer{w: w, columns: columns}
+	return pw, pw.write(magic)
+}
+
+func (pw *Writer) write(b []byte) error {
+	n, err := pw.w.Write(b)
+	pw.offset += int64(n)
+	return err
+}
+
+// WriteRows appends a row group. Each row holds a value per column: int64,
+// float64 or string according to the column type, or nil for optional columns.
+func (pw *Writer) WriteRows(rows [][]interface{}) error {
+	if pw.closed {
+		return ErrClosed
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	//all pages are encoded first, so invalid rows do not corrupt the file
+	pages := make([][]byte, len(pw.columns))
+	for idx, column := range pw.columns {
+		page, err := encodePage(column, idx, rows)
+		if err != nil {
+			return err
+		}
+		pages[idx] = page
+	}
+
+	if pw.footer > 0 {
+		//Flush checked that the writer is a seeker
+		_, err := pw.w.(io.Seeker).Seek(-pw.footer, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		pw.offset -= pw.footer
+		pw.footer = 0
+	}
+
+	group := rowGroup{numRows: int64(len(rows))}
+	for _, page := range pages {
+		header := pageHeader(len(rows), len(page))
+		chunk := columnChunk{
+			offset:           pw.offset,
+			numValues:        int64(len(rows)),
+			uncompressedSize: int64(len(header) + len(page)),
+		}
+
+		err := pw.write(header)
+		if err != nil {
+			return err
+		}
+		err = pw.write(page)
+		if err != nil {
+			return err
+		}
+
+		group.chunks = append(group.chunks, chunk)
+		group.totalSize += chunk.uncompressedSize
+	}
+
+	pw.rowGroups = append(pw.rowGroups, group)
+	pw.numRows += group.numRows
+	return nil
+}
+
+// Flush writes the file metadata of the row groups written so far, so the
+// file can be read while it is still written. The next WriteRows overwrites
+// the metadata, hence the underlying writer has to implement io.Seeker.
+func (pw *Writer) Flush() error {
+	if pw.closed {
+		return ErrClosed
+	}
+	if pw.footer > 0 {
+		return nil
+	}
+	if _, ok := pw.w.(io.Seeker); !ok {
+		return errors.New("flush requires an io.Seeker")
+	}
+
+	start := pw.offset
+	err := pw.writeFooter()
+	pw.footer = pw.offset - start
+	return err
+}
+
+// Close writes the file metadata unless it was already written by Flush. It
+// does not close the underlying writer.
+func (pw *Writer) Close() error {
+	if pw.closed {
+		return nil
+	}
+	pw.closed = true
+
+	if pw.footer > 0 {
+		return nil
+	}
+
+	return pw.writeFooter()
+}
+
+func (pw *Writer) writeFooter() error {
+	meta := pw.fileMetaData()
+	err := pw.write(meta)
+	if err != nil {
+		return err
+	}
+
+	var length [4]byte
+	binary.LittleEndian.PutUint32(length[:], uint32(len(meta)))
+	err = pw.write(length[:])
+	if err != nil {
+		return err
+	}
+
+	return pw.write(magic)
+}
+
+// encodePage encodes the definition levels (for optional columns) and the
+// PLAIN encoded non-null values of a column
+func encodePage(column Column, idx int, rows [][]interface{}) ([]byte, error) {
+	var values bytes.Buffer
+	levels := make([]bool, len(rows))
+	for i, row := range rows {
+		if len(row) <= idx {
+			return nil, fmt.Errorf("row %v has no value for column %v", i, column.Name)
+		}
+
+		value := row[idx]
+		if value == nil {
+			if !column.Optional {
+				return nil, fmt.Errorf("row %v: column %v is required", i, column.Name)
+			}
+			continue
+		}
+		levels[i] = true
+
+		err := encodeValue(&values, column, value)
+		if err != nil {
+			return nil, fmt.Errorf("row %v: %v", i, err)
+		}
+	}
+
+	if !column.Optional {
+		return values.Bytes(), nil
+	}
+
+	//data page v1: definition levels are prefixed by their length
+	encodedLevels := encodeLevels(levels)
+	page := make([]byte, 4, 4+len(encodedLevels)+values.Len())
+	binary.LittleEndian.PutUint32(page, uint32(len(encodedLevels)))
+	page = append(page, encodedLevels...)
+	return append(page, values.Bytes()...), nil
+}
+
+func encodeValue(buf *bytes.Buffer, column Column, value interface{}) error {
+	var b [8]byte
+	switch column.Type {
+	case Int64:
+		v, ok := value.(int64)
+		if !ok {
+			return fmt.Errorf("column %v expects int64, got %T", column.Name, value)
+		}
+		binary.LittleEndian.PutUint64(b[:], uint64(v))
+		buf.Write(b[:])
+	case Double:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("column %v expects float64, got %T", column.Name, value)
+		}
+		binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+		buf.Write(b[:])
+	case String:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("column %v expects string, got %T", column.Name, value)
+		}
+		binary.LittleEndian.PutUint32(b[:4], uint32(len(v)))
+		buf.Write(b[:4])
+		buf.WriteString(v)
+	default:
+		return fmt.Errorf("column %v has unknown type %v", column.Name, column.Type)
+	}
+
+	return nil
+}
+
+// encodeLevels encodes definition levels (max level 1) with the RLE/bit-packing
+// hybrid, using RLE runs only
+func encodeLevels(levels []bool) []byte {
+	var buf []byte
+	var varint [binary.MaxVarintLen64]byte
+	for i := 0; i < len(levels); {
+		j := i
+		for j < len(levels) && levels[j] == levels[i] {
+			j++
+		}
+
+		n := binary.PutUvarint(varint[:], uint64(j-i)<<1)
+		buf = append(buf, varint[:n]...)
+		if levels[i] {
+			buf = append(buf, 1)
+		} else {
+			buf = append(buf, 0)
+		}
+		i = j
+	}
+
+	return buf
+}
+
+func pageHeader(numValues int, size int) []byte {
+	c := newCompactWriter()
+	c.I32(1, pageTypeData)
+	c.I32(2, int32(size)) //uncompressed_page_size
+	c.I32(3, int32(size)) //compressed_page_size
+	c.Struct(5, func() {
+		c.I32(1, int32(numValues))
+		c.I32(2, encodingPlain)
+		c.I32(3, encodingRLE) //definition levels
+		c.I32(4, encodingRLE) //repetition levels
+	})
+	c.buf.WriteByte(0) //stop
+	return c.Bytes()
+}
+
+func (pw *Writer) fileMetaData() []byte {
+	c := newCompactWriter()
+	c.I32(1, 1) //version
+	c.StructList(2, len(pw.columns)+1, func(i int) {
+		if i == 0 {
+			c.String(4, "schema")
+			c.I32(5, int32(len(pw.columns))) //num_children
+			return
+		}
+
+		column := pw.columns[i-1]
+		c.I32(1, physicalType(column.Type))
+		if column.Optional {
+			c.I32(3, repetitionOptional)
+		} else {
+			c.I32(3, repetitionRequired)
+		}
+		c.String(4, column.Name)
+		if column.Type == String {
+			c.I32(6, convertedUTF8)
+		}
+	})
+	c.I64(3, pw.numRows)
+	c.StructList(4, len(pw.rowGroups), func(i int) {
+		group := pw.rowGroups[i]
+		c.StructList(1, len(group.chunks), func(j int) {
+			chunk := group.chunks[j]
+			column := pw.columns[j]
+			c.I64(2, chunk.offset) //file_offset
+			c.Struct(3, func() {
+				c.I32(1, physicalType(column.Type))
+				c.I32List(2, []int32{encodingPlain, encodingRLE})
+				c.StringList(3, []string{column.Name})
+				c.I32(4, codecUncompressed)
+				c.I64(5, chunk.numValues)
+				c.I64(6, chunk.uncompressedSize)
+				c.I64(7, chunk.uncompressedSize)
+				c.I64(9, chunk.offset) //data_page_offset
+			})
+		})
+		c.I64(2, group.totalSize)
+		c.I64(3, group.numRows)
+	})
+	c.String(6, "ethereum-feeestimator")
+	c.buf.WriteByte(0) //stop
+	return c.Bytes()
+}
+
+func physicalType(t Type) int32 {
+	switch t {
+	case Double:
+		return physicalDouble
+	case String:
+		return physicalByteArray
+	default:
+		return physicalInt64
+	}
+}
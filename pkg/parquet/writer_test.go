@@ -0,0 +1,76 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testColumns = []Column{
+	{Name: "block_number", Type: Int64},
+	{Name: "tier", Type: String},
+	{Name: "overpayment_percent", Type: Double, Optional: true},
+}
+
+func TestWriterLayout(t *testing.T) {
+	// arrange
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, testColumns)
+	require.NoError(t, err)
+
+	// act
+	require.NoError(t, w.WriteRows([][]interface{}{{int64(1), "fast", 1.5}, {int64(2), "slow", nil}}))
+	require.NoError(t, w.Close())
+
+	// assert
+	data := buf.Bytes()
+	assert.Equal(t, "PAR1", string(data[:4]))
+	assert.Equal(t, "PAR1", string(data[len(data)-4:]))
+	footer := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	assert.True(t, footer > 0 && footer < len(data)-12)
+}
+
+func TestWriterRejectsInvalidValues(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, testColumns)
+	require.NoError(t, err)
+
+	assert.Error(t, w.WriteRows([][]interface{}{{nil, "fast", 1.5}}))
+	assert.Error(t, w.WriteRows([][]interface{}{{1, "fast", 1.5}}))
+	assert.Error(t, w.WriteRows([][]interface{}{{int64(1), "fast"}}))
+}
+
+func TestFlushIsOverwrittenByNextRowGroup(t *testing.T) {
+	// arrange
+	rows := [][][]interface{}{
+		{{int64(1), "fast", 1.5}},
+		{{int64(2), "slow", nil}, {int64(3), "standard", 0.25}},
+	}
+	flushed, err := os.Create(filepath.Join(t.TempDir(), "flushed.parquet"))
+	require.NoError(t, err)
+	defer flushed.Close()
+	var closed bytes.Buffer
+
+	// act
+	fw, err := NewWriter(flushed, testColumns)
+	require.NoError(t, err)
+	cw, err := NewWriter(&closed, testColumns)
+	require.NoError(t, err)
+	for _, group := range rows {
+		require.NoError(t, fw.WriteRows(group))
+		require.NoError(t, fw.Flush())
+		require.NoError(t, cw.WriteRows(group))
+	}
+	require.NoError(t, fw.Close())
+	require.NoError(t, cw.Close())
+
+	// assert
+	data, err := os.ReadFile(flushed.Name())
+	require.NoError(t, err)
+	assert.Equal(t, closed.Bytes(), data)
+}
@@ -0,0 +1,263 @@
+// Package report compares the scores of the estimators in an HTML or
+// Markdown report.
+package report
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+)
+
+// Record is the outcome of the prediction of a tier
+type Record struct {
+	Estimator          string
+	BlockNumber        int64
+	Time               int64 //0 if unknown, i.e. loaded from csv
+	Tier               string
+	Price              int64
+	Included           bool //within the horizon
+	Blocks             int64
+	Seconds            int64
+	OverpaymentWei     int64
+	OverpaymentPercent float64
+
+	Claim          *scoring.Claim //nil if the estimator made no claim
+	HorizonBlocks  int64          //blocks scored after the prediction
+	HorizonSeconds int64          //seconds between the prediction and the last scored block
+}
+
+type recordKey struct {
+	estimator   string
+	blockNumber int64
+	tier        string
+}
+
+// Load reads the records of the given score files and directories. JSON
+// Lines (.jsonl) and csv files are supported, a prediction contained in
+// multiple files is only loaded once.
+func Load(paths []string) ([]*Record, error) {
+	files, err := scoreFiles(paths)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no score files found in %v", paths)
+	}
+
+	seen := make(map[recordKey]bool)
+	var records []*Record
+	for _, file := range files {
+		var loaded []*Record
+		switch filepath.Ext(file) {
+		case ".jsonl":
+			loaded, err = loadJSONLines(file)
+		case ".csv":
+			loaded, err = loadCSV(file)
+		default:
+			err = fmt.Errorf("unsupported score file %v, use jsonl or csv", file)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range loaded {
+			key := recordKey{record.Estimator, record.BlockNumber, record.Tier}
+			if !seen[key] {
+				seen[key] = true
+				records = append(records, record)
+			}
+		}
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].BlockNumber < records[j].BlockNumber
+	})
+	return records, nil
+}
+
+// scoreFiles expands directories to the score files they contain, JSON
+// Lines files first since they contain the block times
+func scoreFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		for _, pattern := range []string{"*scores*.jsonl", "*scores*.csv"} {
+			matches, err := filepath.Glob(filepath.Join(path, pattern))
+			if err != nil {
+				return nil, err
+			}
+			sort.Strings(matches)
+			files = append(files, matches...)
+		}
+	}
+
+	return files, nil
+}
+
+type jsonInclusion struct {
+	Blocks             int64   `json:"blocks"`
+	Seconds            int64   `json:"seconds"`
+	OverpaymentWei     int64   `json:"overpaymentWei"`
+	OverpaymentPercent float64 `json:"overpaymentPercent"`
+}
+
+type jsonBlockScore struct {
+	Offset int64 `json:"offset"`
+	Time   int64 `json:"time"`
+}
+
+type jsonPrediction struct {
+	Estimator   string                    `json:"estimator"`
+	BlockNumber int64                     `json:"blockNumber"`
+	Time        int64                     `json:"time"`
+	Prices      map[string]int64          `json:"prices"`
+	Blocks      []jsonBlockScore          `json:"blocks"`
+	Inclusion   map[string]*jsonInclusion `json:"inclusion"`
+	Claims      map[string]*scoring.Claim `json:"claims"`
+}
+
+func loadJSONLines(path string) ([]*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []*Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+
+		var prediction jsonPrediction
+		err = json.Unmarshal(scanner.Bytes(), &prediction)
+		if err != nil {
+			return nil, fmt.Errorf("%v:%v: %v", path, line, err)
+		}
+
+		var horizonBlocks, horizonSeconds int64
+		for _, block := range prediction.Blocks {
+			if block.Offset > horizonBlocks {
+				horizonBlocks = block.Offset
+				horizonSeconds = block.Time - prediction.Time
+			}
+		}
+
+		for tier, price := range prediction.Prices {
+			record := &Record{
+				Estimator:      prediction.Estimator,
+				BlockNumber:    prediction.BlockNumber,
+				Time:           prediction.Time,
+				Tier:           tier,
+				Price:          price,
+				Claim:          prediction.Claims[tier],
+				HorizonBlocks:  horizonBlocks,
+				HorizonSeconds: horizonSeconds,
+			}
+			if inclusion := prediction.Inclusion[tier]; inclusion != nil {
+				record.Included = true
+				record.Blocks = inclusion.Blocks
+				record.Seconds = inclusion.Seconds
+				record.OverpaymentWei = inclusion.OverpaymentWei
+				record.OverpaymentPercent = inclusion.OverpaymentPercent
+			}
+			records = append(records, record)
+		}
+	}
+
+	return records, scanner.Err()
+}
+
+// loadCSV reads the csv output, the estimator is taken from the file name
+// (<name>scores<time>.csv)
+func loadCSV(path string) ([]*Record, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	estimator := filepath.Base(path)
+	if idx := strings.Index(estimator, "scores"); idx >= 0 {
+		estimator = estimator[:idx]
+	}
+
+	columns := make(map[string]int, len(rows[0]))
+	var tiers []string
+	for i, name := range rows[0] {
+		columns[name] = i
+		if strings.HasPrefix(name, "price") {
+			tier := strings.TrimPrefix(name, "price")
+			tiers = append(tiers, strings.ToLower(tier[:1])+tier[1:])
+		}
+	}
+
+	var records []*Record
+	for line, row := range rows[1:] {
+		number := func(column string) int64 {
+			idx, ok := columns[column]
+			if !ok || idx >= len(row) {
+				return -1
+			}
+			value, parseErr := strconv.ParseFloat(row[idx], 64)
+			if parseErr != nil {
+				err = fmt.Errorf("%v:%v: %v", path, line+2, parseErr)
+			}
+			return int64(value)
+		}
+
+		blockNumber := number("block_number")
+		for _, tier := range tiers {
+			name := strings.ToUpper(tier[:1]) + tier[1:]
+			record := &Record{
+				Estimator:   estimator,
+				BlockNumber: blockNumber,
+				Tier:        tier,
+				Price:       number("price" + name),
+				Blocks:      number("inclusionBlocks" + name),
+				Seconds:     number("inclusionSeconds" + name),
+			}
+			if record.Blocks >= 0 {
+				record.Included = true
+				record.OverpaymentWei = number("overpaymentWei" + name)
+				if idx, ok := columns["overpaymentPercent"+name]; ok && idx < len(row) {
+					record.OverpaymentPercent, _ = strconv.ParseFloat(row[idx], 64)
+				}
+			} else {
+				record.Blocks, record.Seconds = 0, 0
+			}
+			records = append(records, record)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return records, nil
+}
@@ -0,0 +1,259 @@
+package report
+
+import (
+	"encoding/base64"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/calibration"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+)
+
+// Report compares the estimators
+type Report struct {
+	Estimators  []string
+	Tiers       []string
+	FromBlock   int64
+	ToBlock     int64
+	Iterations  int
+	Confidence  float64
+	Summaries   []*TierSummary
+	Comparisons []*Comparison
+	Charts      []*Chart
+
+	Calibration      []*calibration.Curve
+	Targets          []*calibration.Target
+	ReliabilityChart string
+}
+
+// Chart is the price chart of a tier
+type Chart struct {
+	Tier string
+	SVG  string
+}
+
+// New computes the report of the records
+func New(records []*Record, bootstrap *Bootstrap) *Report {
+	report := &Report{
+		Iterations:  bootstrap.Iterations,
+		Confidence:  bootstrap.Confidence,
+		Summaries:   Summarize(records, bootstrap),
+		Comparisons: Compare(records, bootstrap),
+	}
+
+	estimators := make(map[string]bool)
+	tiers := make(map[string]bool)
+	for i, record := range records {
+		estimators[record.Estimator] = true
+		tiers[record.Tier] = true
+		if i == 0 || record.BlockNumber < report.FromBlock {
+			report.FromBlock = record.BlockNumber
+		}
+		if record.BlockNumber > report.ToBlock {
+			report.ToBlock = record.BlockNumber
+		}
+	}
+	report.Estimators = keys(estimators)
+	report.Tiers = keys(tiers)
+
+	for _, tier := range report.Tiers {
+		report.Charts = append(report.Charts, &Chart{Tier: tier, SVG: PriceChart(tier, records)})
+	}
+
+	observations := Observations(records)
+	if len(observations) > 0 {
+		report.Calibration = calibration.Reliability(observations, calibration.DefaultBins)
+		report.Targets = calibration.ByTarget(observations)
+		report.ReliabilityChart = ReliabilityChart(report.Calibration)
+	}
+
+	return report
+}
+
+// Observations returns the calibration observations of the records for
+// which the estimator claimed a probability of inclusion
+func Observations(records []*Record) []*calibration.Observation {
+	var observations []*calibration.Observation
+	for _, record := range records {
+		if record.Claim == nil {
+			continue
+		}
+
+		var inclusion *scoring.Inclusion
+		if record.Included {
+			inclusion = &scoring.Inclusion{Blocks: record.Blocks, Seconds: record.Seconds}
+		}
+		observations = append(observations, calibration.Observe(record.Estimator, record.Tier, record.Claim, inclusion, record.HorizonBlocks, record.HorizonSeconds))
+	}
+
+	return observations
+}
+
+func keys(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var funcs = map[string]interface{}{
+	"pct": func(v float64) string { return fmt.Sprintf("%.1f%%", 100*v) },
+	"num": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"mul": func(a float64, b float64) float64 { return a * b },
+	"pval": func(v float64) string {
+		if v < 0.001 {
+			return "<0.001"
+		}
+		return fmt.Sprintf("%.3f", v)
+	},
+	"ci": func(i Interval, scale float64) string {
+		return fmt.Sprintf("[%.2f, %.2f]", scale*i.Low, scale*i.High)
+	},
+	"quantiles": func(values []float64) string {
+		if len(values) == 0 {
+			return "-"
+		}
+		formatted := make([]string, len(values))
+		for i, v := range values {
+			formatted[i] = fmt.Sprintf("%.1f", v)
+		}
+		return strings.Join(formatted, " / ")
+	},
+	"quantileNames": func() string {
+		names := make([]string, len(Quantiles))
+		for i, q := range Quantiles {
+			names[i] = fmt.Sprintf("p%v", int(100*q))
+		}
+		return strings.Join(names, " / ")
+	},
+	"window": func(blocks int64, seconds int64) string {
+		var limits []string
+		if blocks > 0 {
+			limits = append(limits, fmt.Sprintf("%v blocks", blocks))
+		}
+		if seconds > 0 {
+			limits = append(limits, fmt.Sprintf("%v s", seconds))
+		}
+		if len(limits) == 0 {
+			return "-"
+		}
+		return strings.Join(limits, ", ")
+	},
+	"svg": func(svg string) htmltemplate.HTML { return htmltemplate.HTML(svg) },
+	"dataURI": func(svg string) string {
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg))
+	},
+}
+
+const markdownTemplate = `# Estimator comparison
+
+Blocks {{.FromBlock}} to {{.ToBlock}}, estimators: {{range $i, $e := .Estimators}}{{if $i}}, {{end}}{{$e}}{{end}}.
+Confidence intervals ({{pct .Confidence}}) and p-values are computed with {{.Iterations}} bootstrap resamples.
+
+## Hit rate and overpayment per tier
+
+| Estimator | Tier | Predictions | Hit rate | CI | Mean blocks | Mean seconds | Mean overpayment % | CI | Overpayment % {{quantileNames}} |
+|---|---|---:|---:|---|---:|---:|---:|---|---|
+{{range .Summaries}}| {{.Estimator}} | {{.Tier}} | {{.Predictions}} | {{pct .HitRate.Mean}} | {{ci .HitRate 100}} | {{num .MeanBlocks}} | {{num .MeanSeconds}} | {{num .Overpayment.Mean}} | {{ci .Overpayment 1}} | {{quantiles .OverpaymentQuantiles}} |
+{{end}}
+## Paired comparisons
+
+Differences are A - B over the blocks both estimators predicted at, the overpayment only over the pairs where both were included.
+
+| Tier | A | B | Pairs | Hit rate diff (pp) | CI | p | Pairs included | Overpayment diff (%) | CI | p |
+|---|---|---|---:|---:|---|---:|---:|---:|---|---:|
+{{range .Comparisons}}| {{.Tier}} | {{.A}} | {{.B}} | {{.Pairs}} | {{num (mul .HitRateDiff.Mean 100)}} | {{ci .HitRateDiff 100}} | {{pval .HitRateP}} | {{.BothIncluded}} | {{num .OverpaymentDiff.Mean}} | {{ci .OverpaymentDiff 1}} | {{pval .OverpaymentP}} |
+{{end}}
+## Prices
+{{range .Charts}}
+![{{.Tier}}]({{dataURI .SVG}})
+{{end}}{{if .Calibration}}
+## Calibration
+
+Claimed probabilities of inclusion within a window compared to the observed frequencies. Predictions whose horizon ends before their window are censored, included or not.
+
+| Estimator | Observations | Censored | ECE |
+|---|---:|---:|---:|
+{{range .Calibration}}| {{.Estimator}} | {{.Count}} | {{.Censored}} | {{num .ECE}} |
+{{end}}
+| Estimator | Tier | Window | Observations | Censored | Claimed | Observed |
+|---|---|---|---:|---:|---:|---:|
+{{range .Targets}}| {{.Estimator}} | {{.Tier}} | {{window .Blocks .Seconds}} | {{.Count}} | {{.Censored}} | {{pct .Claimed}} | {{pct .Observed}} |
+{{end}}
+![reliability]({{dataURI .ReliabilityChart}})
+{{end}}`
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Estimator comparison</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+</style>
+</head>
+<body>
+<h1>Estimator comparison</h1>
+<p>Blocks {{.FromBlock}} to {{.ToBlock}}, estimators: {{range $i, $e := .Estimators}}{{if $i}}, {{end}}{{$e}}{{end}}.
+Confidence intervals ({{pct .Confidence}}) and p-values are computed with {{.Iterations}} bootstrap resamples.</p>
+
+<h2>Hit rate and overpayment per tier</h2>
+<table>
+<tr><th>Estimator</th><th>Tier</th><th>Predictions</th><th>Hit rate</th><th>CI</th><th>Mean blocks</th><th>Mean seconds</th><th>Mean overpayment %</th><th>CI</th><th>Overpayment % {{quantileNames}}</th></tr>
+{{range .Summaries}}<tr><td>{{.Estimator}}</td><td>{{.Tier}}</td><td>{{.Predictions}}</td><td>{{pct .HitRate.Mean}}</td><td>{{ci .HitRate 100}}</td><td>{{num .MeanBlocks}}</td><td>{{num .MeanSeconds}}</td><td>{{num .Overpayment.Mean}}</td><td>{{ci .Overpayment 1}}</td><td>{{quantiles .OverpaymentQuantiles}}</td></tr>
+{{end}}</table>
+
+<h2>Paired comparisons</h2>
+<p>Differences are A - B over the blocks both estimators predicted at, the overpayment only over the pairs where both were included.</p>
+<table>
+<tr><th>Tier</th><th>A</th><th>B</th><th>Pairs</th><th>Hit rate diff (pp)</th><th>CI</th><th>p</th><th>Pairs included</th><th>Overpayment diff (%)</th><th>CI</th><th>p</th></tr>
+{{range .Comparisons}}<tr><td>{{.Tier}}</td><td>{{.A}}</td><td>{{.B}}</td><td>{{.Pairs}}</td><td>{{num (mul .HitRateDiff.Mean 100)}}</td><td>{{ci .HitRateDiff 100}}</td><td>{{pval .HitRateP}}</td><td>{{.BothIncluded}}</td><td>{{num .OverpaymentDiff.Mean}}</td><td>{{ci .OverpaymentDiff 1}}</td><td>{{pval .OverpaymentP}}</td></tr>
+{{end}}</table>
+
+<h2>Prices</h2>
+{{range .Charts}}<div>{{svg .SVG}}</div>
+{{end}}
+{{if .Calibration}}<h2>Calibration</h2>
+<p>Claimed probabilities of inclusion within a window compared to the observed frequencies. Predictions whose horizon ends before their window are censored, included or not.</p>
+<table>
+<tr><th>Estimator</th><th>Observations</th><th>Censored</th><th>ECE</th></tr>
+{{range .Calibration}}<tr><td>{{.Estimator}}</td><td>{{.Count}}</td><td>{{.Censored}}</td><td>{{num .ECE}}</td></tr>
+{{end}}</table>
+<table>
+<tr><th>Estimator</th><th>Tier</th><th>Window</th><th>Observations</th><th>Censored</th><th>Claimed</th><th>Observed</th></tr>
+{{range .Targets}}<tr><td>{{.Estimator}}</td><td>{{.Tier}}</td><td>{{window .Blocks .Seconds}}</td><td>{{.Count}}</td><td>{{.Censored}}</td><td>{{pct .Claimed}}</td><td>{{pct .Observed}}</td></tr>
+{{end}}</table>
+<div>{{svg .ReliabilityChart}}</div>
+{{end}}</body>
+</html>
+`
+
+// WriteMarkdown writes the report as Markdown, the charts are embedded as
+// data URIs
+func (r *Report) WriteMarkdown(w io.Writer) error {
+	tmpl, err := texttemplate.New("report").Funcs(funcs).Parse(markdownTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, r)
+}
+
+// WriteHTML writes the report as a self-contained HTML page
+func (r *Report) WriteHTML(w io.Writer) error {
+	tmpl, err := htmltemplate.New("report").Funcs(funcs).Parse(htmlTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, r)
+}
@@ -0,0 +1,69 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadCSV(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	path := filepath.Join(dir, "expressscores2020-01-01T00:00:00Z.csv")
+	data := "block_number,priceStandard,scoreStandardPlus1,inclusionBlocksStandard,inclusionSecondsStandard,overpaymentWeiStandard,overpaymentPercentStandard\n" +
+		"10,2000000000,0.500,1,12,1000000000,100.000\n" +
+		"11,1000000000,0.100,-1,-1,-1,-1\n"
+	require.NoError(t, os.WriteFile(path, []byte(data), 0660))
+
+	// act
+	records, err := Load([]string{dir})
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, &Record{Estimator: "express", BlockNumber: 10, Tier: "standard", Price: 2000000000, Included: true, Blocks: 1, Seconds: 12, OverpaymentWei: 1000000000, OverpaymentPercent: 100}, records[0])
+	assert.False(t, records[1].Included)
+}
+
+func TestBootstrapMean(t *testing.T) {
+	// arrange
+	bootstrap := NewBootstrap(500, 0.95, 1)
+	values := []float64{1, 1, 1, 1, 0, 1, 1, 1, 1, 1}
+
+	// act
+	interval, p := bootstrap.Mean(values)
+
+	// assert
+	assert.InDelta(t, 0.9, interval.Mean, 1e-9)
+	assert.True(t, interval.Low <= 0.9 && interval.High >= 0.9)
+	assert.True(t, p < 0.01)
+}
+
+func TestCompareUsesPairedBlocks(t *testing.T) {
+	// arrange
+	var records []*Record
+	for block := int64(1); block <= 20; block++ {
+		records = append(records,
+			&Record{Estimator: "a", BlockNumber: block, Tier: "standard", Price: 10, Included: true, OverpaymentPercent: 20},
+			&Record{Estimator: "b", BlockNumber: block, Tier: "standard", Price: 5, Included: block%2 == 0, OverpaymentPercent: 5},
+		)
+	}
+	records = append(records, &Record{Estimator: "a", BlockNumber: 21, Tier: "standard", Included: true})
+
+	// act
+	comparisons := Compare(records, NewBootstrap(200, 0.95, 1))
+	var html bytes.Buffer
+	require.NoError(t, New(records, NewBootstrap(200, 0.95, 1)).WriteHTML(&html))
+
+	// assert
+	require.Len(t, comparisons, 1)
+	assert.Equal(t, 20, comparisons[0].Pairs)
+	assert.Equal(t, 10, comparisons[0].BothIncluded)
+	assert.InDelta(t, 0.5, comparisons[0].HitRateDiff.Mean, 1e-9)
+	assert.InDelta(t, 15, comparisons[0].OverpaymentDiff.Mean, 1e-9)
+	assert.Contains(t, html.String(), "<svg")
+}
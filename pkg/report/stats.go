@@ -0,0 +1,256 @@
+package report
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// Quantiles of the overpayment distribution shown in the report
+var Quantiles = []float64{0.1, 0.25, 0.5, 0.75, 0.9}
+
+// Interval is an estimate with its bootstrap confidence interval
+type Interval struct {
+	Mean float64
+	Low  float64
+	High float64
+}
+
+// TierSummary describes the predictions of an estimator for a tier
+type TierSummary struct {
+	Estimator   string
+	Tier        string
+	Predictions int
+	HitRate     Interval
+	MeanBlocks  float64 //of the included predictions
+	MeanSeconds float64 //of the included predictions
+
+	Overpayment          Interval  //mean overpayment in % of the included predictions
+	OverpaymentQuantiles []float64 //see Quantiles
+}
+
+// Comparison is the paired comparison of two estimators on a tier, based on
+// the predictions both made at the same blocks. The differences are A - B.
+type Comparison struct {
+	Tier  string
+	A     string
+	B     string
+	Pairs int
+
+	HitRateDiff     Interval
+	HitRateP        float64 //two sided bootstrap p-value of the difference
+	BothIncluded    int     //pairs used for the overpayment difference
+	OverpaymentDiff Interval
+	OverpaymentP    float64
+}
+
+// Bootstrap resamples the data to estimate confidence intervals
+type Bootstrap struct {
+	Iterations int
+	Confidence float64 //e.g. 0.95
+	rng        *rand.Rand
+}
+
+// NewBootstrap creates a bootstrap with a fixed seed, so reports are reproducible
+func NewBootstrap(iterations int, confidence float64, seed int64) *Bootstrap {
+	return &Bootstrap{
+		Iterations: iterations,
+		Confidence: confidence,
+		rng:        rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Mean returns the mean of the values, its confidence interval and the two
+// sided p-value of the mean being 0
+func (b *Bootstrap) Mean(values []float64) (Interval, float64) {
+	if len(values) == 0 {
+		return Interval{}, 1
+	}
+
+	means := make([]float64, b.Iterations)
+	for i := range means {
+		sum := 0.0
+		for range values {
+			sum += values[b.rng.Intn(len(values))]
+		}
+		means[i] = sum / float64(len(values))
+	}
+	sort.Float64s(means)
+
+	alpha := (1 - b.Confidence) / 2
+	interval := Interval{
+		Mean: mean(values),
+		Low:  quantile(means, alpha),
+		High: quantile(means, 1-alpha),
+	}
+
+	below, above := 0, 0
+	for _, m := range means {
+		if m <= 0 {
+			below++
+		}
+		if m >= 0 {
+			above++
+		}
+	}
+	p := 2 * math.Min(float64(below), float64(above)) / float64(len(means))
+	return interval, math.Min(p, 1)
+}
+
+// Summarize computes the summaries per estimator and tier
+func Summarize(records []*Record, bootstrap *Bootstrap) []*TierSummary {
+	groups := make(map[[2]string][]*Record)
+	for _, record := range records {
+		key := [2]string{record.Estimator, record.Tier}
+		groups[key] = append(groups[key], record)
+	}
+
+	summaries := make([]*TierSummary, 0, len(groups))
+	for _, key := range sortedKeys(groups) {
+		group := groups[key]
+		hits := make([]float64, len(group))
+		var overpayments []float64
+		blocks, seconds := 0.0, 0.0
+		for i, record := range group {
+			if record.Included {
+				hits[i] = 1
+				overpayments = append(overpayments, record.OverpaymentPercent)
+				blocks += float64(record.Blocks)
+				seconds += float64(record.Seconds)
+			}
+		}
+
+		summary := &TierSummary{
+			Estimator:   key[0],
+			Tier:        key[1],
+			Predictions: len(group),
+		}
+		summary.HitRate, _ = bootstrap.Mean(hits)
+		if len(overpayments) > 0 {
+			summary.MeanBlocks = blocks / float64(len(overpayments))
+			summary.MeanSeconds = seconds / float64(len(overpayments))
+			summary.Overpayment, _ = bootstrap.Mean(overpayments)
+
+			sorted := append([]float64(nil), overpayments...)
+			sort.Float64s(sorted)
+			for _, q := range Quantiles {
+				summary.OverpaymentQuantiles = append(summary.OverpaymentQuantiles, quantile(sorted, q))
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
+// Compare computes the paired comparisons of all estimators sharing a tier
+func Compare(records []*Record, bootstrap *Bootstrap) []*Comparison {
+	byTier := make(map[string]map[string]map[int64]*Record) //tier -> estimator -> block -> record
+	for _, record := range records {
+		estimators, ok := byTier[record.Tier]
+		if !ok {
+			estimators = make(map[string]map[int64]*Record)
+			byTier[record.Tier] = estimators
+		}
+		if estimators[record.Estimator] == nil {
+			estimators[record.Estimator] = make(map[int64]*Record)
+		}
+		estimators[record.Estimator][record.BlockNumber] = record
+	}
+
+	var comparisons []*Comparison
+	for _, tier := range tierNames(byTier) {
+		estimators := estimatorNames(byTier[tier])
+		for i, a := range estimators {
+			for _, b := range estimators[i+1:] {
+				comparisons = append(comparisons, compare(tier, a, b, byTier[tier][a], byTier[tier][b], bootstrap))
+			}
+		}
+	}
+
+	return comparisons
+}
+
+func compare(tier string, a string, b string, recordsA map[int64]*Record, recordsB map[int64]*Record, bootstrap *Bootstrap) *Comparison {
+	blocks := make([]int64, 0, len(recordsA))
+	for block := range recordsA {
+		if _, ok := recordsB[block]; ok {
+			blocks = append(blocks, block)
+		}
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i] < blocks[j] })
+
+	hitDiffs := make([]float64, len(blocks))
+	var overpaymentDiffs []float64
+	for i, block := range blocks {
+		ra, rb := recordsA[block], recordsB[block]
+		hitDiffs[i] = indicator(ra.Included) - indicator(rb.Included)
+		if ra.Included && rb.Included {
+			overpaymentDiffs = append(overpaymentDiffs, ra.OverpaymentPercent-rb.OverpaymentPercent)
+		}
+	}
+
+	comparison := &Comparison{Tier: tier, A: a, B: b, Pairs: len(blocks), BothIncluded: len(overpaymentDiffs)}
+	comparison.HitRateDiff, comparison.HitRateP = bootstrap.Mean(hitDiffs)
+	comparison.OverpaymentDiff, comparison.OverpaymentP = bootstrap.Mean(overpaymentDiffs)
+	return comparison
+}
+
+func indicator(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// quantile interpolates the q quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	upper := int(math.Ceil(pos))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+}
+
+func sortedKeys(groups map[[2]string][]*Record) [][2]string {
+	keys := make([][2]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+func tierNames(m map[string]map[string]map[int64]*Record) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func estimatorNames(m map[string]map[int64]*Record) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
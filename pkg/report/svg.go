@@ -0,0 +1,175 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/calibration"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+const (
+	chartWidth  = 760
+	chartHeight = 260
+	chartMargin = 50
+
+	//maxChartPoints limits the points per series, longer series are thinned out
+	maxChartPoints = 800
+)
+
+var chartColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b"}
+
+type point struct {
+	x float64
+	y float64
+}
+
+// PriceChart draws the predicted prices (gwei) of a tier against the block
+// time, or the block number if the time is unknown, as an SVG line chart per
+// estimator. Prices above the 99th percentile are clipped.
+func PriceChart(tier string, records []*Record) string {
+	useTime := true
+	series := make(map[string][]point)
+	var prices []float64
+	for _, record := range records {
+		if record.Tier != tier {
+			continue
+		}
+		if record.Time == 0 {
+			useTime = false
+		}
+		prices = append(prices, float64(record.Price)/utils.GWei)
+	}
+	if len(prices) == 0 {
+		return ""
+	}
+
+	for _, record := range records {
+		if record.Tier != tier {
+			continue
+		}
+		x := float64(record.BlockNumber)
+		if useTime {
+			x = float64(record.Time)
+		}
+		series[record.Estimator] = append(series[record.Estimator], point{x, float64(record.Price) / utils.GWei})
+	}
+
+	sort.Float64s(prices)
+	maxY := quantile(prices, 0.99) * 1.05
+	if maxY <= 0 {
+		maxY = 1
+	}
+	var minX, maxX int64
+	first := true
+	for _, points := range series {
+		for _, p := range points {
+			if first || int64(p.x) < minX {
+				minX = int64(p.x)
+			}
+			if first || int64(p.x) > maxX {
+				maxX = int64(p.x)
+			}
+			first = false
+		}
+	}
+	if maxX == minX {
+		maxX = minX + 1
+	}
+
+	scaleX := func(x float64) float64 {
+		return chartMargin + (x-float64(minX))/float64(maxX-minX)*(chartWidth-2*chartMargin)
+	}
+	scaleY := func(y float64) float64 {
+		if y > maxY {
+			y = maxY
+		}
+		return chartHeight - chartMargin - y/maxY*(chartHeight-2*chartMargin)
+	}
+	label := func(x int64) string {
+		if useTime {
+			return time.Unix(x, 0).UTC().Format("2006-01-02 15:04")
+		}
+		return fmt.Sprintf("#%v", x)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="sans-serif" font-size="11">`, chartWidth, chartHeight, chartWidth, chartHeight)
+	fmt.Fprintf(&b, `<text x="%v" y="16" font-size="13">%v price (gwei)</text>`, chartMargin, html.EscapeString(tier))
+
+	//y axis with 5 ticks
+	for i := 0; i <= 4; i++ {
+		value := maxY * float64(i) / 4
+		y := scaleY(value)
+		fmt.Fprintf(&b, `<line x1="%v" y1="%.1f" x2="%v" y2="%.1f" stroke="#ddd"/>`, chartMargin, y, chartWidth-chartMargin, y)
+		fmt.Fprintf(&b, `<text x="%v" y="%.1f" text-anchor="end">%.1f</text>`, chartMargin-4, y+4, value)
+	}
+	fmt.Fprintf(&b, `<text x="%v" y="%v">%v</text>`, chartMargin, chartHeight-chartMargin+16, label(minX))
+	fmt.Fprintf(&b, `<text x="%v" y="%v" text-anchor="end">%v</text>`, chartWidth-chartMargin, chartHeight-chartMargin+16, label(maxX))
+
+	names := make([]string, 0, len(series))
+	for name := range series {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		points := series[name]
+		sort.Slice(points, func(i, j int) bool { return points[i].x < points[j].x })
+		step := len(points)/maxChartPoints + 1
+
+		color := chartColors[i%len(chartColors)]
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%v" stroke-width="1.2" points="`, color)
+		for j := 0; j < len(points); j += step {
+			fmt.Fprintf(&b, "%.1f,%.1f ", scaleX(points[j].x), scaleY(points[j].y))
+		}
+		b.WriteString(`"/>`)
+
+		legendX := chartMargin + i*120
+		fmt.Fprintf(&b, `<rect x="%v" y="%v" width="10" height="10" fill="%v"/>`, legendX, chartHeight-18, color)
+		fmt.Fprintf(&b, `<text x="%v" y="%v">%v</text>`, legendX+14, chartHeight-9, html.EscapeString(name))
+	}
+	b.WriteString(`</svg>`)
+
+	return b.String()
+}
+
+// ReliabilityChart draws the reliability curves, a calibrated estimator lies
+// on the diagonal
+func ReliabilityChart(curves []*calibration.Curve) string {
+	const size = 360
+	scale := func(v float64) float64 {
+		return chartMargin + v*(size-2*chartMargin)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v" viewBox="0 0 %v %v" font-family="sans-serif" font-size="11">`, size+140, size, size+140, size)
+	fmt.Fprintf(&b, `<text x="%v" y="16" font-size="13">observed against claimed probability</text>`, chartMargin)
+	for i := 0; i <= 4; i++ {
+		v := float64(i) / 4
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`, scale(0), size-scale(v), scale(1), size-scale(v))
+		fmt.Fprintf(&b, `<text x="%v" y="%.1f" text-anchor="end">%.2f</text>`, chartMargin-4, size-scale(v)+4, v)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%v" text-anchor="middle">%.2f</text>`, scale(v), size-chartMargin+16, v)
+	}
+	fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#999" stroke-dasharray="4"/>`, scale(0), size-scale(0), scale(1), size-scale(1))
+
+	for i, curve := range curves {
+		color := chartColors[i%len(chartColors)]
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%v" stroke-width="1.5" points="`, color)
+		for _, bin := range curve.Bins {
+			fmt.Fprintf(&b, "%.1f,%.1f ", scale(bin.MeanClaimed), size-scale(bin.Observed))
+		}
+		b.WriteString(`"/>`)
+		for _, bin := range curve.Bins {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%v"/>`, scale(bin.MeanClaimed), size-scale(bin.Observed), color)
+		}
+
+		fmt.Fprintf(&b, `<rect x="%v" y="%v" width="10" height="10" fill="%v"/>`, size, chartMargin+i*16, color)
+		fmt.Fprintf(&b, `<text x="%v" y="%v">%v</text>`, size+14, chartMargin+i*16+9, html.EscapeString(curve.Estimator))
+	}
+	b.WriteString(`</svg>`)
+
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package scoring
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rotation configures when a new output file is started
+type Rotation struct {
+	MaxRows int           //predictions per file, 0 disables
+	MaxAge  time.Duration //age of a file, 0 disables
+}
+
+// csvSink writes a row per prediction, the columns are described by csvHeader
+type csvSink struct {
+	config Config
+	file   *rotatingFile
+	w      *csv.Writer
+}
+
+func newCSVSink(config Config) *csvSink {
+	s := &csvSink{config: config}
+	s.file = newRotatingFile(config, ".csv", s.open, s.flush)
+	return s
+}
+
+func (s *csvSink) open(f *os.File) error {
+	s.w = csv.NewWriter(f)
+	return s.w.Write(csvHeader(s.config))
+}
+
+func (s *csvSink) flush() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// Write appends the predictions and flushes them to disk
+func (s *csvSink) Write(predictions []*Prediction) error {
+	for _, prediction := range predictions {
+		err := s.file.next()
+		if err != nil {
+			return err
+		}
+
+		err = s.w.Write(csvRecord(s.config, prediction))
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.w == nil {
+		return nil
+	}
+
+	return s.flush()
+}
+
+// Close flushes and closes the current file
+func (s *csvSink) Close() error {
+	return s.file.Close()
+}
+
+// createFile creates a new file, a counter is appended to the name if a
+// file with the same name already exists
+func createFile(dir string, name string, ext string) (*os.File, error) {
+	path := filepath.Join(dir, name+ext)
+	for i := 1; ; i++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0660)
+		if !os.IsExist(err) {
+			return f, err
+		}
+		path = filepath.Join(dir, fmt.Sprintf("%v-%v%v", name, i, ext))
+	}
+}
+
+// csvHeader returns the csv columns, i.e. a price column per tier followed
+// by a score column per tier for each block of the horizon and the blocks and
+// seconds to inclusion as well as the overpayment per tier
+func csvHeader(config Config) []string {
+	header := []string{"block_number"}
+	for _, tier := range config.Tiers {
+		header = append(header, "price"+columnName(tier))
+	}
+	for i := 1; i <= config.Horizon; i++ {
+		for _, tier := range config.Tiers {
+			header = append(header, fmt.Sprintf("score%vPlus%v", columnName(tier), i))
+		}
+	}
+	for _, tier := range config.Tiers {
+		header = append(header,
+			"inclusionBlocks"+columnName(tier),
+			"inclusionSeconds"+columnName(tier),
+			"overpaymentWei"+columnName(tier),
+			"overpaymentPercent"+columnName(tier),
+		)
+	}
+
+	return header
+}
+
+// csvRecord formats a prediction according to csvHeader, missing values are -1
+func csvRecord(config Config, prediction *Prediction) []string {
+	blockNum := prediction.BlockNumber
+	record := []string{strconv.FormatInt(blockNum, 10)}
+	for _, tier := range config.Tiers {
+		record = append(record, strconv.FormatInt(prediction.Prices[tier], 10))
+	}
+	for i := blockNum + 1; i <= blockNum+int64(config.Horizon); i++ {
+		score, ok := prediction.Scores[i]
+		for _, tier := range config.Tiers {
+			if !ok {
+				record = append(record, strconv.Itoa(-1))
+			} else {
+				record = append(record, strconv.FormatFloat(score.Scores[tier], 'f', 3, 64))
+			}
+		}
+	}
+	for _, tier := range config.Tiers {
+		inclusion, ok := prediction.Inclusion[tier]
+		if !ok {
+			record = append(record, strconv.Itoa(-1), strconv.Itoa(-1), strconv.Itoa(-1), strconv.Itoa(-1))
+		} else {
+			record = append(record,
+				strconv.FormatInt(inclusion.Blocks, 10),
+				strconv.FormatInt(inclusion.Seconds, 10),
+				strconv.FormatInt(inclusion.OverpaymentWei, 10),
+				strconv.FormatFloat(inclusion.OverpaymentPercent, 'f', 3, 64),
+			)
+		}
+	}
+
+	return record
+}
+
+func columnName(tier string) string {
+	if tier == "" {
+		return tier
+	}
+
+	return strings.ToUpper(tier[:1]) + tier[1:]
+}
@@ -0,0 +1,34 @@
+package scoring
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVSinkRotatesAfterMaxRows(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	w := newCSVSink(Config{Name: "test", OutputDir: dir, Rotation: Rotation{MaxRows: 2}})
+
+	// act
+	require.NoError(t, w.Write([]*Prediction{NewPrediction(1, nil), NewPrediction(2, nil)}))
+	require.NoError(t, w.Write([]*Prediction{NewPrediction(3, nil)}))
+	require.NoError(t, w.Close())
+
+	// assert
+	files, err := filepath.Glob(filepath.Join(dir, "testscores*.csv"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	var contents []string
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		contents = append(contents, string(data))
+	}
+	assert.ElementsMatch(t, []string{"block_number\n1\n2\n", "block_number\n3\n"}, contents)
+}
@@ -0,0 +1,116 @@
+package scoring
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+)
+
+// jsonPrediction is a line of the JSON Lines output. In contrast to the csv
+// output the block scores are nested, so the horizon does not change the
+// schema.
+type jsonPrediction struct {
+	Estimator   string                `json:"estimator"`
+	BlockNumber int64                 `json:"blockNumber"`
+	Time        int64                 `json:"time"`
+	Prices      map[string]int64      `json:"prices"`
+	Blocks      []jsonBlockScore      `json:"blocks"`
+	Inclusion   map[string]*Inclusion `json:"inclusion"` //tier -> inclusion, null if not included within the horizon
+	Claims      map[string]*Claim     `json:"claims,omitempty"`
+}
+
+type jsonBlockScore struct {
+	BlockNumber int64              `json:"blockNumber"`
+	Offset      int64              `json:"offset"` //blocks since the prediction
+	Time        int64              `json:"time"`
+	NumberOfTxs int                `json:"numberOfTxs"`
+	Scores      map[string]float64 `json:"scores"`
+	Included    map[string]bool    `json:"included"`
+}
+
+// jsonLinesSink writes a JSON object per prediction and line
+type jsonLinesSink struct {
+	config Config
+	file   *rotatingFile
+	w      *bufio.Writer
+}
+
+func newJSONLinesSink(config Config) *jsonLinesSink {
+	s := &jsonLinesSink{config: config}
+	s.file = newRotatingFile(config, ".jsonl", s.open, s.flush)
+	return s
+}
+
+func (s *jsonLinesSink) open(f *os.File) error {
+	s.w = bufio.NewWriter(f)
+	return nil
+}
+
+func (s *jsonLinesSink) flush() error {
+	return s.w.Flush()
+}
+
+// Write appends the predictions and flushes them to disk
+func (s *jsonLinesSink) Write(predictions []*Prediction) error {
+	for _, prediction := range predictions {
+		err := s.file.next()
+		if err != nil {
+			return err
+		}
+
+		line, err := json.Marshal(jsonRecord(s.config, prediction))
+		if err != nil {
+			return err
+		}
+
+		_, err = s.w.Write(append(line, '\n'))
+		if err != nil {
+			return err
+		}
+	}
+
+	if s.w == nil {
+		return nil
+	}
+
+	return s.flush()
+}
+
+// Close flushes and closes the current file
+func (s *jsonLinesSink) Close() error {
+	return s.file.Close()
+}
+
+func jsonRecord(config Config, prediction *Prediction) *jsonPrediction {
+	record := &jsonPrediction{
+		Estimator:   config.Name,
+		BlockNumber: prediction.BlockNumber,
+		Time:        prediction.Time,
+		Prices:      prediction.Prices,
+		Blocks:      make([]jsonBlockScore, 0, config.Horizon),
+		Inclusion:   make(map[string]*Inclusion, len(config.Tiers)),
+		Claims:      prediction.Claims,
+	}
+
+	for i := prediction.BlockNumber + 1; i <= prediction.BlockNumber+int64(config.Horizon); i++ {
+		score, ok := prediction.Scores[i]
+		if !ok {
+			continue
+		}
+
+		record.Blocks = append(record.Blocks, jsonBlockScore{
+			BlockNumber: i,
+			Offset:      i - prediction.BlockNumber,
+			Time:        score.Time,
+			NumberOfTxs: score.NumberOfTxs,
+			Scores:      score.Scores,
+			Included:    score.Included,
+		})
+	}
+
+	for _, tier := range config.Tiers {
+		record.Inclusion[tier] = prediction.Inclusion[tier]
+	}
+
+	return record
+}
@@ -0,0 +1,34 @@
+package scoring
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONRecordNestsBlockScores(t *testing.T) {
+	// arrange
+	config := Config{Name: "naive", Tiers: []string{"fast", "slow"}, Horizon: 2}
+	prediction := NewPrediction(10, map[string]int64{"fast": 20, "slow": 10})
+	prediction.Scores[11] = &BlockScore{
+		Scores:      map[string]float64{"fast": 0.5, "slow": 0.9},
+		Included:    map[string]bool{"fast": true, "slow": false},
+		NumberOfTxs: 4,
+	}
+	prediction.Inclusion["fast"] = &Inclusion{BlockNumber: 11, Blocks: 1}
+
+	// act
+	line, err := json.Marshal(jsonRecord(config, prediction))
+	require.NoError(t, err)
+
+	// assert
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(line, &record))
+	assert.Equal(t, "naive", record["estimator"])
+	assert.Len(t, record["blocks"], 1)
+	inclusion := record["inclusion"].(map[string]interface{})
+	assert.Nil(t, inclusion["slow"])
+	assert.Equal(t, float64(1), inclusion["fast"].(map[string]interface{})["blocks"])
+}
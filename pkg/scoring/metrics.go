@@ -0,0 +1,69 @@
+package scoring
+
+import (
+	"sort"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// effectiveGasPrices returns the prices per gas the transactions paid in the
+// block (base fee plus effective tip) sorted ascending
+func effectiveGasPrices(block *utils.Block, txs utils.Transactions) []int64 {
+	prices := make([]int64, len(txs))
+	for i, tx := range txs {
+		prices[i] = utils.EffectiveGasPrice(block, tx).Int64()
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i] < prices[j] })
+
+	return prices
+}
+
+// percentageOfTxsWithBiggerGP returns the percentage of the transactions
+// (effective gas prices sorted ascending) that pay more than the prediction
+func percentageOfTxsWithBiggerGP(prices []int64, prediction int64) float64 {
+	for idx, price := range prices {
+		if price > prediction {
+			percentage := (1.0 - (float64(idx) / float64(len(prices)))) * 100.0 //(1-idx/txs)*100
+			return percentage
+		}
+	}
+
+	return 0
+}
+
+// VirtualTxGas is the gas used by the virtual tx checked for inclusion
+var VirtualTxGas = int64(21000)
+
+// isIncluded reports whether a virtual tx paying price would have been
+// included in the block, i.e. it pays at least the lowest effective market
+// price of the block (sorted ascending) and the block had gas capacity left.
+// Blocks without market txs include nothing.
+func isIncluded(block *utils.Block, prices []int64, price int64) bool {
+	if len(prices) == 0 || price < prices[0] {
+		return false
+	}
+
+	if block.GasLimit == nil || block.GasUsed == nil {
+		return false
+	}
+
+	capacity := block.GasLimit.ToInt().Int64() - block.GasUsed.ToInt().Int64()
+	return capacity >= VirtualTxGas
+}
+
+// clearingPrice returns the minimum price that would have been included in
+// the block, i.e. its lowest effective market price (sorted ascending)
+func clearingPrice(prices []int64) int64 {
+	return prices[0]
+}
+
+// overpayment returns the excess of price over the clearing price in wei and
+// percent of the clearing price
+func overpayment(price int64, clearing int64) (int64, float64) {
+	excess := price - clearing
+	if clearing == 0 {
+		return excess, 0
+	}
+
+	return excess, float64(excess) / float64(clearing) * 100.0
+}
@@ -0,0 +1,148 @@
+package scoring
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/parquet"
+)
+
+// parquetSink writes a row per prediction and tier. The rows of a Write are
+// appended as a row group and the file metadata is rewritten, so the file can
+// be read while the estimator is running.
+type parquetSink struct {
+	config  Config
+	columns []parquet.Column
+	file    *rotatingFile
+	w       *parquet.Writer
+	rows    [][]interface{}
+}
+
+func newParquetSink(config Config) *parquetSink {
+	s := &parquetSink{
+		config:  config,
+		columns: parquetColumns(config),
+	}
+	s.file = newRotatingFile(config, ".parquet", s.open, s.close)
+	return s
+}
+
+// parquetColumns returns the schema, the inclusion, claim and score columns
+// are null if the price was not included, the estimator made no claim or the
+// block was not scored
+func parquetColumns(config Config) []parquet.Column {
+	columns := []parquet.Column{
+		{Name: "estimator", Type: parquet.String},
+		{Name: "block_number", Type: parquet.Int64},
+		{Name: "block_time", Type: parquet.Int64},
+		{Name: "tier", Type: parquet.String},
+		{Name: "price_wei", Type: parquet.Int64},
+		{Name: "inclusion_block", Type: parquet.Int64, Optional: true},
+		{Name: "inclusion_blocks", Type: parquet.Int64, Optional: true},
+		{Name: "inclusion_seconds", Type: parquet.Int64, Optional: true},
+		{Name: "clearing_price_wei", Type: parquet.Int64, Optional: true},
+		{Name: "overpayment_wei", Type: parquet.Int64, Optional: true},
+		{Name: "overpayment_percent", Type: parquet.Double, Optional: true},
+		{Name: "claim_probability", Type: parquet.Double, Optional: true},
+		{Name: "claim_blocks", Type: parquet.Int64, Optional: true},
+		{Name: "claim_seconds", Type: parquet.Int64, Optional: true},
+	}
+	for i := 1; i <= config.Horizon; i++ {
+		columns = append(columns, parquet.Column{Name: fmt.Sprintf("score_plus_%v", i), Type: parquet.Double, Optional: true})
+	}
+
+	return columns
+}
+
+func (s *parquetSink) open(f *os.File) error {
+	var err error
+	s.w, err = parquet.NewWriter(f, s.columns)
+	return err
+}
+
+func (s *parquetSink) flush() error {
+	if len(s.rows) == 0 {
+		return nil
+	}
+
+	err := s.w.WriteRows(s.rows)
+	s.rows = nil
+	if err != nil {
+		return err
+	}
+
+	return s.w.Flush()
+}
+
+func (s *parquetSink) close() error {
+	err := s.flush()
+	if err != nil {
+		return err
+	}
+
+	return s.w.Close()
+}
+
+// Write appends the predictions as a row group
+func (s *parquetSink) Write(predictions []*Prediction) error {
+	for _, prediction := range predictions {
+		err := s.file.next()
+		if err != nil {
+			return err
+		}
+
+		s.rows = append(s.rows, parquetRows(s.config, prediction)...)
+	}
+
+	if s.w == nil {
+		return nil
+	}
+
+	return s.flush()
+}
+
+// Close writes the metadata and closes the current file
+func (s *parquetSink) Close() error {
+	return s.file.Close()
+}
+
+func parquetRows(config Config, prediction *Prediction) [][]interface{} {
+	rows := make([][]interface{}, 0, len(config.Tiers))
+	for _, tier := range config.Tiers {
+		row := []interface{}{config.Name, prediction.BlockNumber, prediction.Time, tier, prediction.Prices[tier]}
+
+		inclusion, ok := prediction.Inclusion[tier]
+		if ok {
+			row = append(row,
+				inclusion.BlockNumber,
+				inclusion.Blocks,
+				inclusion.Seconds,
+				inclusion.ClearingPrice,
+				inclusion.OverpaymentWei,
+				inclusion.OverpaymentPercent,
+			)
+		} else {
+			row = append(row, nil, nil, nil, nil, nil, nil)
+		}
+
+		claim, ok := prediction.Claims[tier]
+		if ok {
+			row = append(row, claim.Probability, claim.Blocks, claim.Seconds)
+		} else {
+			row = append(row, nil, nil, nil)
+		}
+
+		for i := prediction.BlockNumber + 1; i <= prediction.BlockNumber+int64(config.Horizon); i++ {
+			score, ok := prediction.Scores[i]
+			if ok {
+				row = append(row, score.Scores[tier])
+			} else {
+				row = append(row, nil)
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
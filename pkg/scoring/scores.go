@@ -0,0 +1,211 @@
+package scoring
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultHorizon is the number of blocks a prediction is compared to
+	DefaultHorizon = 10
+
+	// DefaultOutputDir is the directory the scores are written to
+	DefaultOutputDir = "./output"
+)
+
+// Scores compares the predictions of an estimator to the blocks mined after
+// them. Once a prediction is scored against all blocks of the horizon it is
+// appended to <dir>/<name>scores<time>.<format> and dropped from memory.
+type Scores struct {
+	config      Config
+	predictions map[int64]*Prediction //pending predictions
+	blocks      BlockSource
+	classifier  *utils.TxClassifier
+	logger      *zap.Logger
+	sinks       []Sink
+	written     []map[int64]bool //per sink the complete predictions it wrote
+	totals      map[string]*tierTotals
+}
+
+// NewScores creates a new Scores for the estimator described by config
+func NewScores(config Config, blocks BlockSource, classifier *utils.TxClassifier, logger *zap.Logger) (*Scores, error) {
+	if config.Horizon < 1 {
+		config.Horizon = DefaultHorizon
+	}
+	if config.TxVolume < 1 {
+		config.TxVolume = 1
+	}
+	if config.OutputDir == "" {
+		config.OutputDir = DefaultOutputDir
+	}
+	if len(config.Formats) == 0 {
+		config.Formats = []string{DefaultFormat}
+	}
+
+	sinks := make([]Sink, len(config.Formats))
+	written := make([]map[int64]bool, len(config.Formats))
+	for i, format := range config.Formats {
+		sink, err := NewSink(format, config)
+		if err != nil {
+			return nil, err
+		}
+		sinks[i] = sink
+		written[i] = make(map[int64]bool)
+	}
+
+	totals := make(map[string]*tierTotals, len(config.Tiers))
+	for _, tier := range config.Tiers {
+		totals[tier] = newTierTotals()
+	}
+
+	return &Scores{
+		config:      config,
+		predictions: make(map[int64]*Prediction),
+		blocks:      blocks,
+		classifier:  classifier,
+		logger:      logger,
+		sinks:       sinks,
+		written:     written,
+		totals:      totals,
+	}, nil
+}
+
+// AddPrediction records a prediction, only the first prediction per block is kept
+func (s *Scores) AddPrediction(prediction *Prediction) {
+	_, ok := s.predictions[prediction.BlockNumber]
+	if !ok {
+		s.predictions[prediction.BlockNumber] = prediction
+	}
+}
+
+// PredictScores scores the pending predictions against the blocks mined
+// since and appends the completely scored ones in block order. A prediction
+// is dropped once every sink wrote it, if a sink fails the next call retries
+// it only for the sinks which did not write it yet.
+func (s *Scores) PredictScores() error {
+	var complete []*Prediction
+	for _, pred := range s.predictions {
+		err := s.comparePredictionToNextBlocks(pred)
+		if err != nil {
+			return err
+		}
+
+		if s.complete(pred) {
+			complete = append(complete, pred)
+		}
+	}
+
+	sort.Slice(complete, func(i, j int) bool {
+		return complete[i].BlockNumber < complete[j].BlockNumber
+	})
+
+	for i, sink := range s.sinks {
+		var unwritten []*Prediction
+		for _, pred := range complete {
+			if !s.written[i][pred.BlockNumber] {
+				unwritten = append(unwritten, pred)
+			}
+		}
+
+		err := sink.Write(unwritten)
+		if err != nil {
+			return err
+		}
+		for _, pred := range unwritten {
+			s.written[i][pred.BlockNumber] = true
+		}
+	}
+
+	for _, pred := range complete {
+		s.addToTotals(pred)
+		delete(s.predictions, pred.BlockNumber)
+		for _, written := range s.written {
+			delete(written, pred.BlockNumber)
+		}
+	}
+
+	if len(complete) > 0 {
+		s.logInclusion()
+	}
+	return nil
+}
+
+// Close closes the output files
+func (s *Scores) Close() error {
+	var err error
+	for _, sink := range s.sinks {
+		closeErr := sink.Close()
+		if err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+func (s *Scores) comparePredictionToNextBlocks(predict *Prediction) error {
+	if predict.Time == 0 {
+		block, err := s.blocks.GetBlockByNumber(big.NewInt(predict.BlockNumber))
+		if err != nil {
+			return err
+		}
+		predict.Time = block.Time.ToInt().Int64()
+	}
+
+	for i := predict.BlockNumber + 1; i <= predict.BlockNumber+int64(s.config.Horizon); i++ {
+		_, ok := predict.Scores[i]
+		if !ok {
+			//load transactions of block i
+			block, err := s.blocks.GetBlockByNumber(big.NewInt(i))
+			if err == utils.ErrBlockNotFound {
+				return nil //block does not yet exist
+			}
+
+			if err != nil {
+				return err
+			}
+
+			//miner and builder self-payments and zero priced txs are ignored
+			prices := effectiveGasPrices(block, s.classifier.MarketTransactions(block))
+
+			blockScores := make(map[string]float64, len(s.config.Tiers))
+			included := make(map[string]bool, len(s.config.Tiers))
+			for _, tier := range s.config.Tiers {
+				blockScores[tier] = percentageOfTxsWithBiggerGP(prices, predict.Prices[tier])
+				included[tier] = isIncluded(block, prices, predict.Prices[tier])
+
+				//blocks are scored in order, so the first inclusion is kept
+				if _, ok := predict.Inclusion[tier]; !ok && included[tier] {
+					clearing := clearingPrice(prices)
+					overpaymentWei, overpaymentPercent := overpayment(predict.Prices[tier], clearing)
+					predict.Inclusion[tier] = &Inclusion{
+						BlockNumber:        i,
+						Blocks:             i - predict.BlockNumber,
+						Seconds:            block.Time.ToInt().Int64() - predict.Time,
+						ClearingPrice:      clearing,
+						OverpaymentWei:     overpaymentWei,
+						OverpaymentPercent: overpaymentPercent,
+					}
+				}
+			}
+
+			predict.Scores[i] = &BlockScore{
+				Scores:      blockScores,
+				Included:    included,
+				NumberOfTxs: len(block.Transactions),
+				Time:        block.Time.ToInt().Int64(),
+			}
+		}
+	}
+
+	return nil
+}
+
+// complete reports whether the prediction was scored against all blocks of
+// the horizon
+func (s *Scores) complete(prediction *Prediction) bool {
+	return len(prediction.Scores) >= s.config.Horizon
+}
@@ -0,0 +1,174 @@
+package scoring
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+func TestPercentageOfTxsWithBiggerGP(t *testing.T) {
+	// arrange
+	prices := []int64{1, 2, 3, 4}
+
+	// act & assert
+	assert.Equal(t, 100.0, percentageOfTxsWithBiggerGP(prices, 0))
+	assert.Equal(t, 50.0, percentageOfTxsWithBiggerGP(prices, 2))
+	assert.Equal(t, 0.0, percentageOfTxsWithBiggerGP(prices, 4))
+}
+
+func TestEffectiveGasPrices(t *testing.T) {
+	// arrange
+	dynamicFee := func(tip, feeCap int64) *types.Transaction {
+		return types.NewTx(&types.DynamicFeeTx{GasTipCap: big.NewInt(tip), GasFeeCap: big.NewInt(feeCap)})
+	}
+	txs := utils.Transactions{
+		dynamicFee(2, 100), //pays base fee plus the full tip
+		dynamicFee(5, 12),  //the fee cap limits the tip to 2
+		types.NewTransaction(0, common.Address{}, big.NewInt(0), 0, big.NewInt(11), nil),
+	}
+	london := &utils.Block{BaseFee: (*hexutil.Big)(big.NewInt(10))}
+
+	// act
+	prices := effectiveGasPrices(london, txs)
+	legacyPrices := effectiveGasPrices(&utils.Block{}, txs)
+
+	// assert
+	assert.Equal(t, []int64{11, 12, 12}, prices)
+	assert.Equal(t, []int64{11, 12, 100}, legacyPrices)
+}
+
+func TestHeader(t *testing.T) {
+	// arrange
+	config := Config{Name: "test", Tiers: []string{"slow", "fast"}, Horizon: 2}
+
+	// act
+	header := csvHeader(config)
+
+	// assert
+	assert.Equal(t, []string{
+		"block_number",
+		"priceSlow",
+		"priceFast",
+		"scoreSlowPlus1",
+		"scoreFastPlus1",
+		"scoreSlowPlus2",
+		"scoreFastPlus2",
+		"inclusionBlocksSlow",
+		"inclusionSecondsSlow",
+		"overpaymentWeiSlow",
+		"overpaymentPercentSlow",
+		"inclusionBlocksFast",
+		"inclusionSecondsFast",
+		"overpaymentWeiFast",
+		"overpaymentPercentFast",
+	}, header)
+}
+
+func TestIsIncluded(t *testing.T) {
+	// arrange
+	prices := []int64{5, 8}
+	block := &utils.Block{
+		GasLimit: (*hexutil.Big)(big.NewInt(100000)),
+		GasUsed:  (*hexutil.Big)(big.NewInt(50000)),
+	}
+	fullBlock := &utils.Block{
+		GasLimit: (*hexutil.Big)(big.NewInt(100000)),
+		GasUsed:  (*hexutil.Big)(big.NewInt(90000)),
+	}
+
+	// act & assert
+	assert.True(t, isIncluded(block, prices, 5))
+	assert.False(t, isIncluded(block, prices, 4))
+	assert.False(t, isIncluded(fullBlock, prices, 8))
+	assert.False(t, isIncluded(block, nil, 8))
+}
+
+func TestOverpayment(t *testing.T) {
+	wei, percent := overpayment(15, 10)
+	assert.Equal(t, int64(5), wei)
+	assert.Equal(t, 50.0, percent)
+
+	wei, percent = overpayment(10, 0)
+	assert.Equal(t, int64(10), wei)
+	assert.Equal(t, 0.0, percent)
+}
+
+// chain serves empty blocks up to the head
+type chain struct {
+	head int64
+}
+
+func (c *chain) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	if blockNumber.Int64() > c.head {
+		return nil, utils.ErrBlockNotFound
+	}
+	return &utils.Block{Number: (*hexutil.Big)(blockNumber), Time: (*hexutil.Big)(big.NewInt(12 * blockNumber.Int64()))}, nil
+}
+
+// batchSink records the block numbers of the written batches, failing the
+// given number of writes first
+type batchSink struct {
+	fail    int
+	batches [][]int64
+}
+
+func (s *batchSink) Write(predictions []*Prediction) error {
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("disk full")
+	}
+
+	var batch []int64
+	for _, prediction := range predictions {
+		batch = append(batch, prediction.BlockNumber)
+	}
+	s.batches = append(s.batches, batch)
+	return nil
+}
+
+func (s *batchSink) Close() error {
+	return nil
+}
+
+func TestPredictScoresWritesCompletePredictionsOncePerSink(t *testing.T) {
+	// arrange
+	blocks := &chain{head: 3}
+	scores, err := NewScores(Config{Name: "naive", Tiers: []string{"standard"}, Horizon: 2, OutputDir: t.TempDir()}, blocks, utils.NewTxClassifier(big.NewInt(1)), zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, scores.Close())
+	first, second := &batchSink{}, &batchSink{fail: 1}
+	scores.sinks = []Sink{first, second}
+	scores.written = []map[int64]bool{{}, {}}
+	for _, blockNumber := range []int64{3, 1, 2} {
+		scores.AddPrediction(NewPrediction(blockNumber, map[string]int64{"standard": utils.GWei}))
+	}
+
+	// act
+	failedErr := scores.PredictScores()
+	pendingAfterFailure := len(scores.predictions)
+	blocks.head = 4
+	retriedErr := scores.PredictScores()
+	blocks.head = 5
+	completedErr := scores.PredictScores()
+
+	// assert
+	assert.EqualError(t, failedErr, "disk full")
+	assert.Equal(t, 3, pendingAfterFailure)
+	require.NoError(t, retriedErr)
+	require.NoError(t, completedErr)
+	assert.Equal(t, [][]int64{{1}, {2}, {3}}, first.batches)
+	assert.Equal(t, [][]int64{{1, 2}, {3}}, second.batches)
+	assert.Empty(t, scores.predictions)
+	assert.Empty(t, scores.written[0])
+	assert.Empty(t, scores.written[1])
+	assert.Equal(t, 3, scores.InclusionSummary()["standard"].Predictions)
+}
@@ -0,0 +1,130 @@
+package scoring
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Supported output formats
+const (
+	FormatCSV       = "csv"
+	FormatJSONLines = "jsonl"
+	FormatParquet   = "parquet"
+	DefaultFormat   = FormatCSV
+)
+
+// Formats lists the supported output formats
+var Formats = []string{FormatCSV, FormatJSONLines, FormatParquet}
+
+// Sink receives the completely scored predictions in block order
+type Sink interface {
+	Write(predictions []*Prediction) error
+	Close() error
+}
+
+// NewSink creates a sink writing the scores of the estimator described by
+// config in the given format
+func NewSink(format string, config Config) (Sink, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVSink(config), nil
+	case FormatJSONLines:
+		return newJSONLinesSink(config), nil
+	case FormatParquet:
+		return newParquetSink(config), nil
+	default:
+		return nil, fmt.Errorf("unknown score format %v, supported are %v", format, Formats)
+	}
+}
+
+// rotatingFile tracks the rows of <dir>/<name>scores<time><ext> and starts a
+// new file according to the rotation. open is called for every new file and
+// finish before a file is closed.
+type rotatingFile struct {
+	dir      string
+	name     string
+	ext      string
+	rotation Rotation
+	clock    utils.Clock
+	open     func(f *os.File) error
+	finish   func() error
+
+	file     *os.File
+	rows     int
+	openedAt time.Time
+}
+
+func newRotatingFile(config Config, ext string, open func(f *os.File) error, finish func() error) *rotatingFile {
+	clock := config.Clock
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+
+	return &rotatingFile{
+		dir:      config.OutputDir,
+		name:     config.Name,
+		ext:      ext,
+		rotation: config.Rotation,
+		clock:    clock,
+		open:     open,
+		finish:   finish,
+	}
+}
+
+// next makes sure the open file accepts another row and counts it
+func (r *rotatingFile) next() error {
+	if r.file == nil || r.shouldRotate() {
+		err := r.rotate()
+		if err != nil {
+			return err
+		}
+	}
+
+	r.rows++
+	return nil
+}
+
+func (r *rotatingFile) shouldRotate() bool {
+	if r.rotation.MaxRows > 0 && r.rows >= r.rotation.MaxRows {
+		return true
+	}
+
+	return r.rotation.MaxAge > 0 && r.clock.Now().Sub(r.openedAt) >= r.rotation.MaxAge
+}
+
+func (r *rotatingFile) rotate() error {
+	err := r.Close()
+	if err != nil {
+		return err
+	}
+
+	now := r.clock.Now()
+	f, err := createFile(r.dir, fmt.Sprintf("%vscores%v", r.name, now.Format(time.RFC3339)), r.ext)
+	if err != nil {
+		return err
+	}
+
+	r.file = f
+	r.rows = 0
+	r.openedAt = now
+	return r.open(f)
+}
+
+// Close finishes and closes the current file
+func (r *rotatingFile) Close() error {
+	if r.file == nil {
+		return nil
+	}
+
+	err := r.finish()
+	closeErr := r.file.Close()
+	r.file = nil
+	if err != nil {
+		return err
+	}
+
+	return closeErr
+}
@@ -0,0 +1,99 @@
+package scoring
+
+import (
+	"math/big"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Recorder is fed by the estimators with their predictions. After every
+// prediction PredictScores is called to score the predictions against the
+// blocks mined since.
+type Recorder interface {
+	AddPrediction(prediction *Prediction)
+	PredictScores() error
+}
+
+// BlockSource loads blocks by number, it returns utils.ErrBlockNotFound for
+// blocks which do not exist yet
+type BlockSource interface {
+	GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error)
+}
+
+// Config describes the predictions of an estimator
+type Config struct {
+	Name    string   //name of the estimator, used as prefix for the output files
+	Tiers   []string //names of the predicted tiers, e.g. slow, standard, fast
+	Horizon int      //number of blocks after a prediction it is compared to
+
+	//TxVolume is the assumed number of txs sent per prediction, used to
+	//aggregate the overspend
+	TxVolume int64
+
+	OutputDir string      //directory the scores are written to
+	Formats   []string    //output formats, see Formats
+	Rotation  Rotation    //when to start a new output file
+	Clock     utils.Clock //names and rotates the output files, defaults to the wall clock
+}
+
+// Prediction contains the gas price (in wei) per tier predicted at a block
+type Prediction struct {
+	BlockNumber int64
+	Time        int64                 //timestamp of the block the prediction was made at
+	Prices      map[string]int64      //tier -> price
+	Scores      map[int64]*BlockScore //blocknum -> score
+	Inclusion   map[string]*Inclusion //tier -> first block a tx at the price would have been included in
+	Claims      map[string]*Claim     //tier -> claimed probability of inclusion, if the estimator makes one
+}
+
+// Claim is the probability an estimator claims for a tx at the predicted
+// price to be included within a window of blocks and/or seconds
+type Claim struct {
+	Probability float64 `json:"probability"` //0-1
+	Blocks      int64   `json:"blocks"`      //0 if the window is not limited in blocks
+	Seconds     int64   `json:"seconds"`     //0 if the window is not limited in seconds
+}
+
+// BlockScore is the score of a prediction compared to a single block
+type BlockScore struct {
+	Scores      map[string]float64 //tier -> percentage of txs with a bigger gas price
+	Included    map[string]bool    //tier -> whether a tx at the price would have been included
+	NumberOfTxs int
+	Time        int64 //timestamp of the block
+}
+
+// Inclusion describes when a virtual tx at the predicted price would have
+// been included and how much it overpaid compared to the clearing price,
+// i.e. the minimum price that would have been included in the same block
+type Inclusion struct {
+	BlockNumber        int64   `json:"blockNumber"`
+	Blocks             int64   `json:"blocks"`  //blocks waited since the prediction
+	Seconds            int64   `json:"seconds"` //seconds waited since the prediction
+	ClearingPrice      int64   `json:"clearingPrice"`
+	OverpaymentWei     int64   `json:"overpaymentWei"`
+	OverpaymentPercent float64 `json:"overpaymentPercent"`
+}
+
+// InclusionSummary aggregates the inclusions of the completely scored
+// predictions of a tier
+type InclusionSummary struct {
+	Predictions int
+	HitRate     float64 //share of predictions included within the horizon
+	MeanBlocks  float64 //mean blocks to inclusion of the included predictions
+	MeanSeconds float64 //mean seconds to inclusion of the included predictions
+
+	MeanOverpaymentWei     float64
+	MeanOverpaymentPercent float64
+	CumulativeOverspendWei *big.Int //overpayment * VirtualTxGas * TxVolume over all included predictions
+}
+
+// NewPrediction creates a prediction made at the given block
+func NewPrediction(blockNumber int64, prices map[string]int64) *Prediction {
+	return &Prediction{
+		BlockNumber: blockNumber,
+		Prices:      prices,
+		Scores:      make(map[int64]*BlockScore),
+		Inclusion:   make(map[string]*Inclusion),
+		Claims:      make(map[string]*Claim),
+	}
+}
@@ -0,0 +1,90 @@
+package scoring
+
+import (
+	"math/big"
+
+	"go.uber.org/zap"
+)
+
+// tierTotals accumulates the inclusions of the completely scored predictions
+// of a tier, so they can be dropped from memory
+type tierTotals struct {
+	predictions        int
+	hits               int
+	blocks             float64
+	seconds            float64
+	overpaymentWei     float64
+	overpaymentPercent float64
+	overspendWei       *big.Int
+}
+
+func newTierTotals() *tierTotals {
+	return &tierTotals{overspendWei: new(big.Int)}
+}
+
+func (s *Scores) addToTotals(prediction *Prediction) {
+	txGas := new(big.Int).Mul(big.NewInt(VirtualTxGas), big.NewInt(s.config.TxVolume))
+	for _, tier := range s.config.Tiers {
+		totals := s.totals[tier]
+		totals.predictions++
+
+		inclusion, ok := prediction.Inclusion[tier]
+		if !ok {
+			continue
+		}
+
+		totals.hits++
+		totals.blocks += float64(inclusion.Blocks)
+		totals.seconds += float64(inclusion.Seconds)
+		totals.overpaymentWei += float64(inclusion.OverpaymentWei)
+		totals.overpaymentPercent += inclusion.OverpaymentPercent
+
+		overspend := new(big.Int).Mul(big.NewInt(inclusion.OverpaymentWei), txGas)
+		totals.overspendWei.Add(totals.overspendWei, overspend)
+	}
+}
+
+// InclusionSummary returns per tier the hit rate, the mean time to inclusion
+// and the overpayment of the completely scored predictions
+func (s *Scores) InclusionSummary() map[string]*InclusionSummary {
+	summary := make(map[string]*InclusionSummary, len(s.config.Tiers))
+	for _, tier := range s.config.Tiers {
+		totals := s.totals[tier]
+		sum := &InclusionSummary{
+			Predictions:            totals.predictions,
+			CumulativeOverspendWei: new(big.Int).Set(totals.overspendWei),
+		}
+
+		if totals.predictions > 0 {
+			sum.HitRate = float64(totals.hits) / float64(totals.predictions)
+		}
+		if totals.hits > 0 {
+			hits := float64(totals.hits)
+			sum.MeanBlocks = totals.blocks / hits
+			sum.MeanSeconds = totals.seconds / hits
+			sum.MeanOverpaymentWei = totals.overpaymentWei / hits
+			sum.MeanOverpaymentPercent = totals.overpaymentPercent / hits
+		}
+		summary[tier] = sum
+	}
+
+	return summary
+}
+
+func (s *Scores) logInclusion() {
+	summary := s.InclusionSummary()
+	for _, tier := range s.config.Tiers {
+		sum := summary[tier]
+		s.logger.Info("inclusion and overpayment",
+			zap.String("estimator", s.config.Name),
+			zap.String("tier", tier),
+			zap.Int("predictions", sum.Predictions),
+			zap.Float64("hitRate", sum.HitRate),
+			zap.Float64("meanBlocks", sum.MeanBlocks),
+			zap.Float64("meanSeconds", sum.MeanSeconds),
+			zap.Float64("meanOverpaymentWei", sum.MeanOverpaymentWei),
+			zap.Float64("meanOverpaymentPercent", sum.MeanOverpaymentPercent),
+			zap.Stringer("cumulativeOverspendWei", sum.CumulativeOverspendWei),
+		)
+	}
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Formats an estimate is rendered in, selected by the route
+// (/v1/estimates/{estimator}/{format}), the format query parameter or the
+// format parameter of the Accept header (application/json; format=etherscan)
+const (
+	FormatNative        = "native"
+	FormatEthGasStation = "ethgasstation" //ethgasAPI.json of ETH Gas Station
+	FormatEtherscan     = "etherscan"     //gas tracker oracle of Etherscan
+	FormatEIP1559       = "eip1559"       //low/medium/high fees used by wallet SDKs
+)
+
+var (
+	//Formats are the supported formats
+	Formats = []string{FormatNative, FormatEthGasStation, FormatEtherscan, FormatEIP1559}
+
+	//BlockTime is the expected time between blocks, used to turn claims in
+	//blocks into wait times
+	BlockTime = 12 * time.Second
+)
+
+// levels are the tiers of an estimate the four speeds of the compatibility
+// formats are taken from
+type levels struct {
+	safeLow string
+	average string
+	fast    string
+	fastest string
+}
+
+// levelAliases are the tier names mapped to a speed
+var levelAliases = map[string][]string{
+	"safeLow": {"safeLow", "slow", "low"},
+	"average": {"average", "standard", "medium", "propose"},
+	"fast":    {"fast", "high"},
+	"fastest": {"fastest", "instant", "rapid"},
+}
+
+// levelsOf maps the tiers of the estimate to the four speeds by name. Speeds
+// without a tier of that name fall back to the cheapest (safeLow), the median
+// (average) and the most expensive tier (fast, fastest), so estimators with a
+// single tier quote the same price for all speeds.
+func levelsOf(estimate *Estimate) levels {
+	byPrice := tiersByPrice(estimate)
+	find := func(level string, fallback string) string {
+		for _, alias := range levelAliases[level] {
+			if _, ok := estimate.Prices[alias]; ok {
+				return alias
+			}
+		}
+
+		return fallback
+	}
+
+	if len(byPrice) == 0 {
+		return levels{}
+	}
+	l := levels{
+		safeLow: find("safeLow", byPrice[0]),
+		average: find("average", byPrice[(len(byPrice)-1)/2]),
+		fast:    find("fast", byPrice[len(byPrice)-1]),
+	}
+	l.fastest = find("fastest", l.fast)
+	return l
+}
+
+// tiersByPrice returns the tiers of the estimate ordered by price
+func tiersByPrice(estimate *Estimate) []string {
+	tiers := append([]string(nil), estimate.Tiers...)
+	sort.SliceStable(tiers, func(i, j int) bool {
+		return estimate.Prices[tiers[i]] < estimate.Prices[tiers[j]]
+	})
+
+	return tiers
+}
+
+// waitSeconds returns the wait claimed for the tier, 0 if it claims none
+func waitSeconds(estimate *Estimate, tier string) float64 {
+	claim, ok := estimate.Claims[tier]
+	if !ok {
+		return 0
+	}
+	if claim.Seconds > 0 {
+		return float64(claim.Seconds)
+	}
+
+	return float64(claim.Blocks) * BlockTime.Seconds()
+}
+
+// gwei formats wei as a decimal gwei string
+func gwei(wei int64) string {
+	return strconv.FormatFloat(float64(wei)/utils.GWei, 'f', -1, 64)
+}
+
+// ethGasStationResponse is the ethgasAPI.json format, prices are in 0.1 gwei
+// and waits in minutes
+type ethGasStationResponse struct {
+	SafeLow     float64 `json:"safeLow"`
+	Average     float64 `json:"average"`
+	Fast        float64 `json:"fast"`
+	Fastest     float64 `json:"fastest"`
+	SafeLowWait float64 `json:"safeLowWait"`
+	AvgWait     float64 `json:"avgWait"`
+	FastWait    float64 `json:"fastWait"`
+	FastestWait float64 `json:"fastestWait"`
+	BlockTime   float64 `json:"block_time"`
+	BlockNum    int64   `json:"blockNum"`
+}
+
+func newEthGasStationResponse(estimate *Estimate) ethGasStationResponse {
+	l := levelsOf(estimate)
+	deciGwei := func(tier string) float64 {
+		return float64(estimate.Prices[tier]) / (utils.GWei / 10)
+	}
+	minutes := func(tier string) float64 {
+		return waitSeconds(estimate, tier) / 60
+	}
+
+	return ethGasStationResponse{
+		SafeLow:     deciGwei(l.safeLow),
+		Average:     deciGwei(l.average),
+		Fast:        deciGwei(l.fast),
+		Fastest:     deciGwei(l.fastest),
+		SafeLowWait: minutes(l.safeLow),
+		AvgWait:     minutes(l.average),
+		FastWait:    minutes(l.fast),
+		FastestWait: minutes(l.fastest),
+		BlockTime:   BlockTime.Seconds(),
+		BlockNum:    estimate.BlockNumber,
+	}
+}
+
+// etherscanResponse is the format of the Etherscan gas tracker oracle, all
+// values are strings and prices are in gwei
+type etherscanResponse struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Result  etherscanResult `json:"result"`
+}
+
+type etherscanResult struct {
+	LastBlock       string `json:"LastBlock"`
+	SafeGasPrice    string `json:"SafeGasPrice"`
+	ProposeGasPrice string `json:"ProposeGasPrice"`
+	FastGasPrice    string `json:"FastGasPrice"`
+	SuggestBaseFee  string `json:"suggestBaseFee"`
+	GasUsedRatio    string `json:"gasUsedRatio"`
+}
+
+func newEtherscanResponse(estimate *Estimate) etherscanResponse {
+	l := levelsOf(estimate)
+	return etherscanResponse{
+		Status:  "1",
+		Message: "OK",
+		Result: etherscanResult{
+			LastBlock:       strconv.FormatInt(estimate.BlockNumber, 10),
+			SafeGasPrice:    gwei(estimate.Prices[l.safeLow]),
+			ProposeGasPrice: gwei(estimate.Prices[l.average]),
+			FastGasPrice:    gwei(estimate.Prices[l.fast]),
+			SuggestBaseFee:  gwei(estimate.BaseFee),
+			GasUsedRatio:    strconv.FormatFloat(estimate.GasUsedRatio, 'f', -1, 64),
+		},
+	}
+}
+
+// eip1559Response is the low/medium/high format of wallet SDKs, fees are
+// gwei strings and waits in milliseconds
+type eip1559Response struct {
+	Low              eip1559Level `json:"low"`
+	Medium           eip1559Level `json:"medium"`
+	High             eip1559Level `json:"high"`
+	EstimatedBaseFee string       `json:"estimatedBaseFee"`
+}
+
+type eip1559Level struct {
+	SuggestedMaxPriorityFeePerGas string `json:"suggestedMaxPriorityFeePerGas"`
+	SuggestedMaxFeePerGas         string `json:"suggestedMaxFeePerGas"`
+	MinWaitTimeEstimate           int64  `json:"minWaitTimeEstimate"`
+	MaxWaitTimeEstimate           int64  `json:"maxWaitTimeEstimate"`
+}
+
+// newEIP1559Level splits the gas price of the tier into the priority fee above
+// the next base fee, the max fee allows the base fee to double
+func newEIP1559Level(estimate *Estimate, tier string) eip1559Level {
+	price := estimate.Prices[tier]
+	tip := price - estimate.BaseFee
+	if tip < 0 {
+		tip = 0
+	}
+
+	level := eip1559Level{
+		SuggestedMaxPriorityFeePerGas: gwei(tip),
+		SuggestedMaxFeePerGas:         gwei(2*estimate.BaseFee + tip),
+	}
+	if wait := waitSeconds(estimate, tier); wait > 0 {
+		level.MinWaitTimeEstimate = BlockTime.Milliseconds()
+		level.MaxWaitTimeEstimate = int64(math.Max(wait, BlockTime.Seconds()) * 1000)
+	}
+
+	return level
+}
+
+func newEIP1559Response(estimate *Estimate) eip1559Response {
+	l := levelsOf(estimate)
+	return eip1559Response{
+		Low:              newEIP1559Level(estimate, l.safeLow),
+		Medium:           newEIP1559Level(estimate, l.average),
+		High:             newEIP1559Level(estimate, l.fast),
+		EstimatedBaseFee: gwei(estimate.BaseFee),
+	}
+}
+
+// render returns the response body of the estimate in the given format
+func render(format string, estimate *Estimate, now time.Time) (interface{}, error) {
+	switch format {
+	case FormatNative:
+		return newEstimateResponse(estimate, now), nil
+	case FormatEthGasStation:
+		return newEthGasStationResponse(estimate), nil
+	case FormatEtherscan:
+		return newEtherscanResponse(estimate), nil
+	case FormatEIP1559:
+		return newEIP1559Response(estimate), nil
+	default:
+		return nil, fmt.Errorf("unknown format %v, supported are %v", format, strings.Join(Formats, ", "))
+	}
+}
+
+// requestedFormat returns the format selected by the query or the Accept
+// header of the request, FormatNative if none is selected
+func requestedFormat(r *http.Request) string {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err == nil && params["format"] != "" {
+			return params["format"]
+		}
+	}
+
+	return FormatNative
+}
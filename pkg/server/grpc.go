@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/feeestimatorpb"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/web3j"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// CurveSource answers probability curve queries, it is implemented by the
+// web3j estimator
+type CurveSource interface {
+	Query(maxWaitSeconds int64, probability float64, sampleSize int64) (*web3j.Estimate, error)
+}
+
+// GRPCService implements the FeeEstimator gRPC service on the snapshot
+type GRPCService struct {
+	feeestimatorpb.UnimplementedFeeEstimatorServer
+
+	snapshot *Snapshot
+	curves   CurveSource //nil if no web3j estimator is running
+}
+
+// NewGRPCService creates the service, curves may be nil
+func NewGRPCService(snapshot *Snapshot, curves CurveSource) *GRPCService {
+	return &GRPCService{snapshot: snapshot, curves: curves}
+}
+
+// GetEstimate returns the latest estimate of an estimator
+func (s *GRPCService) GetEstimate(ctx context.Context, request *feeestimatorpb.GetEstimateRequest) (*feeestimatorpb.Estimate, error) {
+	estimate, ok := s.snapshot.Get(request.Estimator)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no estimate of %v", request.Estimator)
+	}
+
+	estimate, ok = Filter{Tiers: request.Tiers}.apply(estimate)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%v has none of the tiers %v", request.Estimator, request.Tiers)
+	}
+
+	return newProtoEstimate(estimate, s.snapshot.Now()), nil
+}
+
+// WatchEstimates streams the current and every new estimate of the selected
+// estimators until the client cancels
+func (s *GRPCService) WatchEstimates(request *feeestimatorpb.WatchEstimatesRequest, stream feeestimatorpb.FeeEstimator_WatchEstimatesServer) error {
+	subscription := s.snapshot.Subscribe(Filter{Estimators: request.Estimators, Tiers: request.Tiers})
+	defer s.snapshot.Unsubscribe(subscription)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-subscription.Ready():
+			for _, estimate := range subscription.Next() {
+				err := stream.Send(newProtoEstimate(estimate, s.snapshot.Now()))
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// GetProbabilityCurve queries the web3j estimator for the curve
+func (s *GRPCService) GetProbabilityCurve(ctx context.Context, request *feeestimatorpb.GetProbabilityCurveRequest) (*feeestimatorpb.ProbabilityCurve, error) {
+	if s.curves == nil {
+		return nil, status.Error(codes.Unavailable, "the web3j estimator is not running")
+	}
+	if request.MaxWaitSeconds <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "max_wait_seconds must be positive, got %v", request.MaxWaitSeconds)
+	}
+	if request.Probability <= 0 || request.Probability > 100 {
+		return nil, status.Errorf(codes.InvalidArgument, "probability must be in (0, 100], got %v", request.Probability)
+	}
+
+	estimate, err := s.curves.Query(request.MaxWaitSeconds, request.Probability, request.SampleSize)
+	if err == web3j.ErrNoEstimate || err == web3j.ErrNoTransactions {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	points := make([]*feeestimatorpb.ProbabilityPoint, len(estimate.Probabilities))
+	for i, probability := range estimate.Probabilities {
+		points[i] = &feeestimatorpb.ProbabilityPoint{
+			GasPriceWei: probability.GasPrice,
+			Probability: probability.Probability,
+		}
+	}
+
+	return &feeestimatorpb.ProbabilityCurve{
+		BlockNumber: estimate.BlockNumber,
+		WaitBlocks:  estimate.WaitBlocks,
+		GasPriceWei: estimate.GasPrice,
+		Points:      points,
+	}, nil
+}
+
+func newProtoEstimate(estimate *Estimate, now time.Time) *feeestimatorpb.Estimate {
+	tiers := make([]*feeestimatorpb.Tier, len(estimate.Tiers))
+	for i, tier := range estimate.Tiers {
+		price := estimate.Prices[tier]
+		tiers[i] = &feeestimatorpb.Tier{
+			Name:      tier,
+			PriceWei:  price,
+			PriceGwei: float64(price) / utils.GWei,
+		}
+		if claim, ok := estimate.Claims[tier]; ok {
+			tiers[i].Claim = &feeestimatorpb.Claim{
+				Probability: claim.Probability,
+				Blocks:      claim.Blocks,
+				Seconds:     claim.Seconds,
+			}
+		}
+	}
+
+	return &feeestimatorpb.Estimate{
+		Estimator:   estimate.Estimator,
+		BlockNumber: estimate.BlockNumber,
+		UpdatedAt:   timestamppb.New(estimate.UpdatedAt),
+		AgeSeconds:  now.Sub(estimate.UpdatedAt).Seconds(),
+		Tiers:       tiers,
+		Parameters:  estimate.Parameters,
+		BaseFeeWei:  estimate.BaseFee,
+	}
+}
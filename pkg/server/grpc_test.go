@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/feeestimatorpb"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/web3j"
+)
+
+// fixedCurve answers every query with the same estimate
+type fixedCurve struct {
+	estimate *web3j.Estimate
+}
+
+func (c *fixedCurve) Query(maxWaitSeconds int64, probability float64, sampleSize int64) (*web3j.Estimate, error) {
+	return c.estimate, nil
+}
+
+// dial serves the service in memory and returns a client connected to it
+func dial(t *testing.T, service *GRPCService) feeestimatorpb.FeeEstimatorClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	feeestimatorpb.RegisterFeeEstimatorServer(server, service)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return feeestimatorpb.NewFeeEstimatorClient(conn)
+}
+
+func TestGRPCServiceServesEstimates(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	publish(snapshot, "web3j", 100, map[string]int64{"slow": 1000000000, "fast": 3000000000})
+	client := dial(t, NewGRPCService(snapshot, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// act
+	estimate, err := client.GetEstimate(ctx, &feeestimatorpb.GetEstimateRequest{Estimator: "web3j", Tiers: []string{"fast"}})
+	require.NoError(t, err)
+	_, unknownErr := client.GetEstimate(ctx, &feeestimatorpb.GetEstimateRequest{Estimator: "naive"})
+	_, curveErr := client.GetProbabilityCurve(ctx, &feeestimatorpb.GetProbabilityCurveRequest{MaxWaitSeconds: 60, Probability: 90})
+
+	stream, err := client.WatchEstimates(ctx, &feeestimatorpb.WatchEstimatesRequest{Estimators: []string{"web3j"}})
+	require.NoError(t, err)
+	current, err := stream.Recv()
+	require.NoError(t, err)
+	publish(snapshot, "web3j", 101, map[string]int64{"slow": 2, "fast": 4})
+	update, err := stream.Recv()
+	require.NoError(t, err)
+
+	// assert
+	require.Len(t, estimate.Tiers, 1)
+	assert.Equal(t, "fast", estimate.Tiers[0].Name)
+	assert.Equal(t, int64(3000000000), estimate.Tiers[0].PriceWei)
+	assert.Equal(t, 3.0, estimate.Tiers[0].PriceGwei)
+	assert.Equal(t, codes.NotFound, status.Code(unknownErr))
+	assert.Equal(t, codes.Unavailable, status.Code(curveErr))
+	assert.Equal(t, int64(100), current.BlockNumber)
+	assert.Equal(t, int64(101), update.BlockNumber)
+	assert.Len(t, update.Tiers, 2)
+}
+
+func TestGRPCServiceServesProbabilityCurve(t *testing.T) {
+	// arrange
+	curves := &fixedCurve{estimate: &web3j.Estimate{
+		GasPrice:    2,
+		BlockNumber: 100,
+		WaitBlocks:  5,
+		Probabilities: []*web3j.Probability{
+			{GasPrice: 3, Probability: 0.99},
+			{GasPrice: 1, Probability: 0.5},
+		},
+	}}
+	client := dial(t, NewGRPCService(NewSnapshot(nil), curves))
+
+	// act
+	curve, err := client.GetProbabilityCurve(context.Background(), &feeestimatorpb.GetProbabilityCurveRequest{MaxWaitSeconds: 60, Probability: 90})
+	_, invalidErr := client.GetProbabilityCurve(context.Background(), &feeestimatorpb.GetProbabilityCurveRequest{MaxWaitSeconds: 60, Probability: 101})
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), curve.GasPriceWei)
+	assert.Equal(t, 5.0, curve.WaitBlocks)
+	require.Len(t, curve.Points, 2)
+	assert.Equal(t, int64(3), curve.Points[0].GasPriceWei)
+	assert.Equal(t, codes.InvalidArgument, status.Code(invalidErr))
+}
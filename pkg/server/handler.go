@@ -0,0 +1,177 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+
+	"go.uber.org/zap"
+)
+
+// EstimatesPath is the path the estimates are served at
+const EstimatesPath = "/v1/estimates"
+
+type tierResponse struct {
+	Name string  `json:"name"`
+	Wei  int64   `json:"wei"`
+	Gwei float64 `json:"gwei"`
+}
+
+type estimateResponse struct {
+	Estimator   string            `json:"estimator"`
+	BlockNumber int64             `json:"blockNumber"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+	AgeSeconds  float64           `json:"ageSeconds"`
+	Tiers       []tierResponse    `json:"tiers"`
+	Parameters  map[string]string `json:"parameters"`
+}
+
+type estimatesResponse struct {
+	Estimates []estimateResponse `json:"estimates"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler serves the estimates of the snapshot as JSON:
+//
+//	GET /v1/estimates                       the estimates of all estimators
+//	GET /v1/estimates/{estimator}           the estimate of one estimator
+//	GET /v1/estimates/{estimator}/{format}  the estimate in a compatibility format
+//
+// The format of a single estimate can also be selected with ?format= or the
+// format parameter of the Accept header (see Formats). The updates are
+// streamed, filtered by ?estimator= and ?tier=, at:
+//
+//	GET /v1/stream     Server-Sent Events
+//	GET /v1/stream/ws  WebSocket
+//
+// See ServeHealth for the health endpoints.
+type Handler struct {
+	snapshot *Snapshot
+	health   HealthConfig
+	head     cachedHead
+	logger   *zap.Logger
+	mux      *http.ServeMux
+}
+
+// NewHandler creates a handler serving the snapshot
+func NewHandler(snapshot *Snapshot, logger *zap.Logger) *Handler {
+	h := &Handler{
+		snapshot: snapshot,
+		logger:   logger,
+		mux:      http.NewServeMux(),
+	}
+	h.mux.HandleFunc(EstimatesPath, h.estimates)
+	h.mux.HandleFunc(EstimatesPath+"/", h.estimate)
+	h.mux.HandleFunc(StreamPath, h.stream)
+	h.mux.Handle(WebSocketPath, h.websocketHandler())
+
+	return h
+}
+
+// ServeHTTP dispatches the request to the routes
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) estimates(w http.ResponseWriter, r *http.Request) {
+	if !h.allowGet(w, r) {
+		return
+	}
+
+	if format := requestedFormat(r); format != FormatNative {
+		h.writeJSON(w, http.StatusNotAcceptable, errorResponse{Error: fmt.Sprintf("format %v is only served per estimator", format)})
+		return
+	}
+
+	now := h.snapshot.Now()
+	response := estimatesResponse{Estimates: []estimateResponse{}}
+	for _, estimate := range h.snapshot.All() {
+		response.Estimates = append(response.Estimates, newEstimateResponse(estimate, now))
+	}
+
+	h.writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) estimate(w http.ResponseWriter, r *http.Request) {
+	if !h.allowGet(w, r) {
+		return
+	}
+
+	//{estimator} or {estimator}/{format}
+	segments := strings.Split(strings.TrimPrefix(r.URL.Path, EstimatesPath+"/"), "/")
+	if segments[0] == "" || len(segments) > 2 {
+		h.writeJSON(w, http.StatusNotFound, errorResponse{Error: "not found"})
+		return
+	}
+
+	format := requestedFormat(r)
+	if len(segments) == 2 {
+		format = segments[1]
+	}
+
+	estimate, ok := h.snapshot.Get(segments[0])
+	if !ok {
+		h.writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("no estimate of %v", segments[0])})
+		return
+	}
+
+	body, err := render(format, estimate, h.snapshot.Now())
+	if err != nil {
+		status := http.StatusNotAcceptable
+		if len(segments) == 2 {
+			status = http.StatusNotFound
+		}
+		h.writeJSON(w, status, errorResponse{Error: err.Error()})
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, body)
+}
+
+// allowGet rejects requests other than GET (and HEAD)
+func (h *Handler) allowGet(w http.ResponseWriter, r *http.Request) bool {
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		return true
+	}
+
+	w.Header().Set("Allow", "GET, HEAD")
+	h.writeJSON(w, http.StatusMethodNotAllowed, errorResponse{Error: "method not allowed"})
+	return false
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		h.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+func newEstimateResponse(estimate *Estimate, now time.Time) estimateResponse {
+	tiers := make([]tierResponse, len(estimate.Tiers))
+	for i, tier := range estimate.Tiers {
+		price := estimate.Prices[tier]
+		tiers[i] = tierResponse{
+			Name: tier,
+			Wei:  price,
+			Gwei: float64(price) / utils.GWei,
+		}
+	}
+
+	return estimateResponse{
+		Estimator:   estimate.Estimator,
+		BlockNumber: estimate.BlockNumber,
+		UpdatedAt:   estimate.UpdatedAt,
+		AgeSeconds:  now.Sub(estimate.UpdatedAt).Seconds(),
+		Tiers:       tiers,
+		Parameters:  estimate.Parameters,
+	}
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Paths of the health endpoints
+const (
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+)
+
+// ErrorSource reports the error of the last run, it is implemented by
+// estimator.Estimator
+type ErrorSource interface {
+	Err() error
+}
+
+// Circuit reports the state of a circuit breaker, it is implemented by
+// utils.Breaker
+type Circuit interface {
+	Open() bool
+	Err() error
+}
+
+// HeadSource provides the head of the chain
+type HeadSource interface {
+	GetLastestBlock() (*utils.Block, error)
+}
+
+// HealthConfig configures when the estimates are ready to be served
+type HealthConfig struct {
+	Estimators      map[string]ErrorSource //estimators which have to be ready, by name
+	MaxAge          time.Duration          //estimates older than this are stale, 0 disables
+	MaxBlocksBehind int64                  //estimates further behind the head are stale, 0 disables
+	Head            HeadSource             //required by MaxBlocksBehind
+	HeadMaxAge      time.Duration          //the head is reloaded once older than this, 0 reloads it on every probe
+	Circuit         Circuit                //circuit of the node, may be nil
+}
+
+// cachedHead is the head last loaded by the readiness, probes share it
+// instead of each calling the node
+type cachedHead struct {
+	mutex    sync.Mutex
+	block    *utils.Block
+	err      error
+	loadedAt time.Time
+}
+
+type estimatorStatus struct {
+	Estimator    string   `json:"estimator"`
+	Ready        bool     `json:"ready"`
+	BlockNumber  int64    `json:"blockNumber,omitempty"`
+	AgeSeconds   float64  `json:"ageSeconds,omitempty"`
+	BlocksBehind int64    `json:"blocksBehind,omitempty"`
+	LastError    string   `json:"lastError,omitempty"`
+	Reasons      []string `json:"reasons,omitempty"` //why it is not ready
+}
+
+type circuitStatus struct {
+	Open      bool   `json:"open"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+type readinessResponse struct {
+	Ready      bool              `json:"ready"`
+	Head       int64             `json:"head,omitempty"`
+	HeadError  string            `json:"headError,omitempty"`
+	Circuit    *circuitStatus    `json:"circuit,omitempty"`
+	Estimators []estimatorStatus `json:"estimators"`
+}
+
+// ServeHealth adds the health endpoints of the estimates:
+//
+//	GET /healthz              200 while the server is running
+//	GET /readyz               200 if every estimator is ready, 503 otherwise
+//	GET /readyz/{estimator}   200 if the estimator is ready, 503 otherwise
+//
+// An estimator is ready if it has an estimate which is not stale, its last run
+// succeeded and the circuit of the node is closed. The readiness responses
+// report the status and last error of every estimator.
+func (h *Handler) ServeHealth(config HealthConfig) {
+	h.health = config
+	h.mux.HandleFunc(LivePath, h.live)
+	h.mux.HandleFunc(ReadyPath, h.ready)
+	h.mux.HandleFunc(ReadyPath+"/", h.ready)
+}
+
+func (h *Handler) live(w http.ResponseWriter, r *http.Request) {
+	if !h.allowGet(w, r) {
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (h *Handler) ready(w http.ResponseWriter, r *http.Request) {
+	if !h.allowGet(w, r) {
+		return
+	}
+
+	names := make([]string, 0, len(h.health.Estimators))
+	for name := range h.health.Estimators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if r.URL.Path != ReadyPath {
+		name := strings.TrimPrefix(r.URL.Path, ReadyPath+"/")
+		if _, ok := h.health.Estimators[name]; !ok {
+			h.writeJSON(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("unknown estimator %v", name)})
+			return
+		}
+		names = []string{name}
+	}
+
+	response := h.readiness(names)
+	status := http.StatusOK
+	if !response.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	h.writeJSON(w, status, response)
+}
+
+// readiness checks the named estimators
+func (h *Handler) readiness(names []string) readinessResponse {
+	response := readinessResponse{Ready: true, Estimators: []estimatorStatus{}}
+
+	var circuitOpen bool
+	if h.health.Circuit != nil {
+		circuitOpen = h.health.Circuit.Open()
+		response.Circuit = &circuitStatus{Open: circuitOpen, LastError: errorString(h.health.Circuit.Err())}
+	}
+
+	head := int64(-1)
+	if h.health.MaxBlocksBehind > 0 && h.health.Head != nil {
+		block, err := h.latestHead()
+		if err != nil {
+			response.HeadError = err.Error()
+		} else {
+			head = block.Number.ToInt().Int64()
+			response.Head = head
+		}
+	}
+
+	now := h.snapshot.Now()
+	for _, name := range names {
+		status := estimatorStatus{Estimator: name}
+		if source := h.health.Estimators[name]; source != nil {
+			status.LastError = errorString(source.Err())
+		}
+		if status.LastError != "" {
+			status.Reasons = append(status.Reasons, "the last run failed")
+		}
+		if circuitOpen {
+			status.Reasons = append(status.Reasons, "the circuit of the node is open")
+		}
+		if response.HeadError != "" {
+			status.Reasons = append(status.Reasons, "the head is unknown")
+		}
+
+		estimate, ok := h.snapshot.Get(name)
+		if !ok {
+			status.Reasons = append(status.Reasons, "no estimate yet")
+		} else {
+			status.BlockNumber = estimate.BlockNumber
+			age := now.Sub(estimate.UpdatedAt)
+			status.AgeSeconds = age.Seconds()
+			if h.health.MaxAge > 0 && age > h.health.MaxAge {
+				status.Reasons = append(status.Reasons, fmt.Sprintf("the estimate is older than %v", h.health.MaxAge))
+			}
+
+			if head >= 0 {
+				status.BlocksBehind = head - estimate.BlockNumber
+				if status.BlocksBehind > h.health.MaxBlocksBehind {
+					status.Reasons = append(status.Reasons, fmt.Sprintf("the estimate is more than %v blocks behind", h.health.MaxBlocksBehind))
+				}
+			}
+		}
+
+		status.Ready = len(status.Reasons) == 0
+		response.Ready = response.Ready && status.Ready
+		response.Estimators = append(response.Estimators, status)
+	}
+
+	return response
+}
+
+// latestHead returns the head, loaded at most once per HeadMaxAge
+func (h *Handler) latestHead() (*utils.Block, error) {
+	h.head.mutex.Lock()
+	defer h.head.mutex.Unlock()
+
+	now := h.snapshot.Now()
+	loaded := h.head.block != nil || h.head.err != nil
+	if !loaded || h.health.HeadMaxAge <= 0 || now.Sub(h.head.loadedAt) >= h.health.HeadMaxAge {
+		h.head.block, h.head.err = h.health.Head.GetLastestBlock()
+		h.head.loadedAt = now
+	}
+
+	return h.head.block, h.head.err
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
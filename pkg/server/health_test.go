@@ -0,0 +1,134 @@
+package server
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// lastRun reports a fixed error of the last run
+type lastRun struct {
+	err error
+}
+
+func (r *lastRun) Err() error {
+	return r.err
+}
+
+// circuit is a circuit breaker in a fixed state
+type circuit struct {
+	open bool
+	err  error
+}
+
+func (c *circuit) Open() bool {
+	return c.open
+}
+
+func (c *circuit) Err() error {
+	return c.err
+}
+
+func TestHandlerServesReadiness(t *testing.T) {
+	// arrange
+	clock := &fixedClock{now: time.Unix(1600000000, 0).UTC()}
+	snapshot := NewSnapshot(clock)
+	snapshot.Update(Estimate{Estimator: "naive", BlockNumber: 100})
+	snapshot.Update(Estimate{Estimator: "web3j", BlockNumber: 95})
+	clock.now = clock.now.Add(30 * time.Second)
+
+	express := &lastRun{}
+	node := &circuit{}
+	handler := NewHandler(snapshot, zap.NewNop())
+	handler.ServeHealth(HealthConfig{
+		Estimators:      map[string]ErrorSource{"naive": &lastRun{}, "web3j": &lastRun{}, "express": express},
+		MaxAge:          time.Minute,
+		MaxBlocksBehind: 3,
+		Head:            &headSource{head: &utils.Block{Number: (*hexutil.Big)(big.NewInt(101))}},
+		Circuit:         node,
+	})
+
+	// act
+	var all, naive, failed, open readinessResponse
+	liveStatus := get(t, handler, LivePath, nil)
+	allStatus := get(t, handler, ReadyPath, &all)
+	naiveStatus := get(t, handler, ReadyPath+"/naive", &naive)
+	unknownStatus := get(t, handler, ReadyPath+"/unknown", nil)
+
+	express.err = errors.New("connection refused")
+	snapshot.Update(Estimate{Estimator: "express", BlockNumber: 101})
+	failedStatus := get(t, handler, ReadyPath+"/express", &failed)
+
+	node.open, node.err = true, errors.New("connection refused")
+	clock.now = clock.now.Add(time.Minute)
+	openStatus := get(t, handler, ReadyPath+"/naive", &open)
+
+	// assert
+	assert.Equal(t, http.StatusOK, liveStatus)
+	assert.Equal(t, http.StatusServiceUnavailable, allStatus)
+	assert.False(t, all.Ready)
+	assert.Equal(t, int64(101), all.Head)
+	require.Len(t, all.Estimators, 3)
+	assert.Equal(t, "express", all.Estimators[0].Estimator)
+	assert.Equal(t, []string{"no estimate yet"}, all.Estimators[0].Reasons)
+	assert.True(t, all.Estimators[1].Ready)
+	assert.Equal(t, 30.0, all.Estimators[1].AgeSeconds)
+	assert.Equal(t, int64(6), all.Estimators[2].BlocksBehind)
+	assert.Equal(t, []string{"the estimate is more than 3 blocks behind"}, all.Estimators[2].Reasons)
+
+	assert.Equal(t, http.StatusOK, naiveStatus)
+	assert.True(t, naive.Ready)
+	assert.Len(t, naive.Estimators, 1)
+	assert.Equal(t, http.StatusNotFound, unknownStatus)
+
+	assert.Equal(t, http.StatusServiceUnavailable, failedStatus)
+	assert.Equal(t, "connection refused", failed.Estimators[0].LastError)
+	assert.Equal(t, []string{"the last run failed"}, failed.Estimators[0].Reasons)
+
+	assert.Equal(t, http.StatusServiceUnavailable, openStatus)
+	assert.True(t, open.Circuit.Open)
+	assert.Equal(t, "connection refused", open.Circuit.LastError)
+	assert.Equal(t, []string{"the circuit of the node is open", "the estimate is older than 1m0s"}, open.Estimators[0].Reasons)
+}
+
+func TestReadinessReloadsTheHeadOnceItIsStale(t *testing.T) {
+	// arrange
+	clock := &fixedClock{now: time.Unix(1600000000, 0).UTC()}
+	snapshot := NewSnapshot(clock)
+	snapshot.Update(Estimate{Estimator: "naive", BlockNumber: 100})
+	head := &headSource{head: &utils.Block{Number: (*hexutil.Big)(big.NewInt(101))}}
+	handler := NewHandler(snapshot, zap.NewNop())
+	handler.ServeHealth(HealthConfig{
+		Estimators:      map[string]ErrorSource{"naive": &lastRun{}},
+		MaxBlocksBehind: 3,
+		Head:            head,
+		HeadMaxAge:      5 * time.Second,
+	})
+
+	// act
+	var cached, reloaded readinessResponse
+	get(t, handler, ReadyPath, nil)
+	clock.now = clock.now.Add(4 * time.Second)
+	head.head = &utils.Block{Number: (*hexutil.Big)(big.NewInt(110))}
+	cachedStatus := get(t, handler, ReadyPath+"/naive", &cached)
+	cachedCalls := head.calls
+	clock.now = clock.now.Add(time.Second)
+	reloadedStatus := get(t, handler, ReadyPath, &reloaded)
+
+	// assert
+	assert.Equal(t, http.StatusOK, cachedStatus)
+	assert.Equal(t, int64(101), cached.Head)
+	assert.Equal(t, 1, cachedCalls)
+	assert.Equal(t, http.StatusServiceUnavailable, reloadedStatus)
+	assert.Equal(t, int64(110), reloaded.Head)
+	assert.Equal(t, 2, head.calls)
+}
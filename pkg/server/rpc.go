@@ -0,0 +1,334 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/oracle"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"go.uber.org/zap"
+)
+
+// MaxRequestBytes limits the size of a JSON-RPC request body
+var MaxRequestBytes int64 = 1 << 20
+
+// JSON-RPC error codes
+const (
+	rpcParseError    = -32700
+	rpcInvalidParams = -32602
+	rpcInternalError = -32603
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Proxy forwards a JSON-RPC request body to a node and returns its response body
+type Proxy interface {
+	Forward(body []byte) ([]byte, error)
+}
+
+// HTTPProxy forwards requests to the JSON-RPC endpoint at URL
+type HTTPProxy struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPProxy creates a proxy to the node at url
+func NewHTTPProxy(url string) *HTTPProxy {
+	return &HTTPProxy{url: url, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Forward posts the body to the node
+func (p *HTTPProxy) Forward(body []byte) ([]byte, error) {
+	response, err := p.client.Post(p.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("upstream responded with %v", response.Status)
+	}
+
+	return io.ReadAll(response.Body)
+}
+
+// RPCHandler is a JSON-RPC facade of a node. It answers eth_gasPrice,
+// eth_maxPriorityFeePerGas and eth_feeHistory from the estimate of one
+// estimator and forwards every other method (and the fee methods as long as
+// there is no estimate) to the node.
+type RPCHandler struct {
+	snapshot  *Snapshot
+	estimator string
+	tier      string //"" selects the average speed, see levelsOf
+	proxy     Proxy
+	logger    *zap.Logger
+}
+
+// NewRPCHandler creates a facade answering from the estimate of estimator at
+// tier, the other methods are forwarded to proxy
+func NewRPCHandler(snapshot *Snapshot, estimator string, tier string, proxy Proxy, logger *zap.Logger) *RPCHandler {
+	return &RPCHandler{
+		snapshot:  snapshot,
+		estimator: estimator,
+		tier:      tier,
+		proxy:     proxy,
+		logger:    logger,
+	}
+}
+
+// ServeHTTP answers single and batch requests
+func (h *RPCHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxRequestBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var response interface{}
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		response, err = h.batch(trimmed)
+	} else {
+		response, err = h.single(trimmed)
+	}
+	if err != nil {
+		h.logger.Warn("could not forward request", zap.Error(err))
+		response = newRPCError(nil, rpcInternalError, err.Error())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	switch raw := response.(type) {
+	case []byte:
+		_, err = w.Write(raw)
+	case json.RawMessage:
+		_, err = w.Write(raw)
+	default:
+		err = json.NewEncoder(w).Encode(response)
+	}
+	if err != nil {
+		h.logger.Warn("could not write response", zap.Error(err))
+	}
+}
+
+// single answers a request, forwarded requests return the raw node response
+func (h *RPCHandler) single(body []byte) (interface{}, error) {
+	var request rpcRequest
+	err := json.Unmarshal(body, &request)
+	if err != nil {
+		return newRPCError(nil, rpcParseError, "parse error"), nil
+	}
+
+	response, ok := h.answer(request)
+	if ok {
+		return response, nil
+	}
+
+	return h.proxy.Forward(body)
+}
+
+// batch answers the fee methods of a batch and forwards the others in one
+// batch, the responses are not ordered like the requests
+func (h *RPCHandler) batch(body []byte) (interface{}, error) {
+	var requests []json.RawMessage
+	err := json.Unmarshal(body, &requests)
+	if err != nil {
+		return newRPCError(nil, rpcParseError, "parse error"), nil
+	}
+
+	var responses []json.RawMessage
+	var forward []json.RawMessage
+	for _, raw := range requests {
+		var request rpcRequest
+		err = json.Unmarshal(raw, &request)
+		if err != nil {
+			responses = append(responses, mustMarshal(newRPCError(nil, rpcParseError, "parse error")))
+			continue
+		}
+
+		response, ok := h.answer(request)
+		if !ok {
+			forward = append(forward, raw)
+			continue
+		}
+		responses = append(responses, mustMarshal(response))
+	}
+
+	if len(forward) > 0 {
+		forwarded, err := h.proxy.Forward(mustMarshal(forward))
+		if err != nil {
+			return nil, err
+		}
+
+		var upstream []json.RawMessage
+		err = json.Unmarshal(forwarded, &upstream)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode upstream batch: %v", err)
+		}
+		responses = append(responses, upstream...)
+	}
+
+	return responses, nil
+}
+
+// answer returns the response to a fee method, false if it has to be
+// forwarded. The response is an *rpcResponse or the raw node response.
+func (h *RPCHandler) answer(request rpcRequest) (interface{}, bool) {
+	if request.Method != oracle.MethodGasPrice && request.Method != oracle.MethodMaxPriorityFee && request.Method != oracle.MethodFeeHistory {
+		return nil, false
+	}
+
+	estimate, ok := h.snapshot.Get(h.estimator)
+	if !ok {
+		return nil, false
+	}
+
+	if _, ok := estimate.Prices[h.tier]; h.tier != "" && !ok {
+		return newRPCError(request.ID, rpcInternalError, fmt.Sprintf("%v has no tier %v", h.estimator, h.tier)), true
+	}
+
+	switch request.Method {
+	case oracle.MethodGasPrice:
+		return newRPCResult(request.ID, (*hexutil.Big)(big.NewInt(h.price(estimate, h.tier)))), true
+	case oracle.MethodMaxPriorityFee:
+		return newRPCResult(request.ID, (*hexutil.Big)(big.NewInt(h.tip(estimate, h.tier)))), true
+	default:
+		return h.feeHistory(request, estimate)
+	}
+}
+
+// feeHistory takes the history from the node and replaces the rewards of
+// every block by the tips of the estimate, so clients deriving their fees
+// from the rewards get the estimate. A percentile p is answered with the tip
+// of the tier at p percent of the tiers ordered by price. Errors and empty
+// results of the node are passed through unchanged.
+func (h *RPCHandler) feeHistory(request rpcRequest, estimate *Estimate) (interface{}, bool) {
+	var params []json.RawMessage
+	err := json.Unmarshal(request.Params, &params)
+	if err != nil || len(params) < 2 {
+		return newRPCError(request.ID, rpcInvalidParams, "expected blockCount, newestBlock and rewardPercentiles"), true
+	}
+
+	var percentiles []float64
+	if len(params) > 2 {
+		err = json.Unmarshal(params[2], &percentiles)
+		if err != nil {
+			return newRPCError(request.ID, rpcInvalidParams, "invalid rewardPercentiles"), true
+		}
+	}
+	for i, percentile := range percentiles {
+		if percentile < 0 || percentile > 100 {
+			return newRPCError(request.ID, rpcInvalidParams, fmt.Sprintf("invalid reward percentile: %v", percentile)), true
+		}
+		if i > 0 && percentile < percentiles[i-1] {
+			return newRPCError(request.ID, rpcInvalidParams, fmt.Sprintf("invalid reward percentile: #%d:%v > #%d:%v", i-1, percentiles[i-1], i, percentile)), true
+		}
+	}
+
+	forwarded, err := h.proxy.Forward(mustMarshal(request))
+	if err != nil {
+		h.logger.Warn("could not forward eth_feeHistory", zap.Error(err))
+		return newRPCError(request.ID, rpcInternalError, err.Error()), true
+	}
+
+	var upstream struct {
+		Result *oracle.FeeHistory `json:"result"`
+		Error  *rpcError          `json:"error"`
+	}
+	err = json.Unmarshal(forwarded, &upstream)
+	if err != nil {
+		return newRPCError(request.ID, rpcInternalError, "could not decode upstream response"), true
+	}
+	if upstream.Error != nil || upstream.Result == nil {
+		return json.RawMessage(forwarded), true
+	}
+
+	tiers := tiersByPrice(estimate)
+	if len(percentiles) > 0 && len(tiers) > 0 {
+		rewards := make([]*hexutil.Big, len(percentiles))
+		for i, percentile := range percentiles {
+			tier := tiers[int(percentile/100*float64(len(tiers)-1)+0.5)]
+			rewards[i] = (*hexutil.Big)(big.NewInt(h.tip(estimate, tier)))
+		}
+		for i := range upstream.Result.Reward {
+			upstream.Result.Reward[i] = rewards
+		}
+	}
+
+	return newRPCResult(request.ID, upstream.Result), true
+}
+
+// price returns the gas price of the tier, "" selects the average speed
+func (h *RPCHandler) price(estimate *Estimate, tier string) int64 {
+	if tier == "" {
+		tier = levelsOf(estimate).average
+	}
+
+	return estimate.Prices[tier]
+}
+
+// tip returns the priority fee of the tier above the next base fee
+func (h *RPCHandler) tip(estimate *Estimate, tier string) int64 {
+	tip := h.price(estimate, tier) - estimate.BaseFee
+	if tip < 0 {
+		return 0
+	}
+
+	return tip
+}
+
+func newRPCResult(id json.RawMessage, result interface{}) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: rpcID(id), Result: result}
+}
+
+func newRPCError(id json.RawMessage, code int, message string) *rpcResponse {
+	return &rpcResponse{JSONRPC: "2.0", ID: rpcID(id), Error: &rpcError{Code: code, Message: message}}
+}
+
+// rpcID returns the id of the response, null if the request had none
+func rpcID(id json.RawMessage) json.RawMessage {
+	if len(id) == 0 {
+		return json.RawMessage("null")
+	}
+
+	return id
+}
+
+// mustMarshal encodes values that always encode
+func mustMarshal(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
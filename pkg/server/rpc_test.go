@@ -0,0 +1,148 @@
+package server
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// fakeProxy answers forwarded requests with a fixed body and keeps them
+type fakeProxy struct {
+	response  string
+	forwarded []string
+}
+
+func (p *fakeProxy) Forward(body []byte) ([]byte, error) {
+	p.forwarded = append(p.forwarded, string(body))
+	return []byte(p.response), nil
+}
+
+func post(t *testing.T, handler http.Handler, body string, response interface{}) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), response))
+}
+
+func TestRPCHandlerAnswersFeeMethodsAndForwardsOthers(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	proxy := &fakeProxy{response: `{"jsonrpc":"2.0","id":1,"result":"0x64"}`}
+	handler := NewRPCHandler(snapshot, "naive", "", proxy, zap.NewNop())
+
+	var beforeEstimate map[string]interface{}
+	post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"eth_gasPrice"}`, &beforeEstimate)
+
+	head := &utils.Block{
+		BaseFee:  (*hexutil.Big)(big.NewInt(10 * utils.GWei)),
+		GasLimit: (*hexutil.Big)(big.NewInt(30000000)),
+		GasUsed:  (*hexutil.Big)(big.NewInt(15000000)),
+	}
+	publisher := NewPublisher(snapshot, &headSource{head: head}, "naive", []string{"standard"}, nil, &recorder{})
+	publisher.AddPrediction(scoring.NewPrediction(100, map[string]int64{"standard": 12 * utils.GWei}))
+
+	// act
+	var gasPrice, tip, blockNumber map[string]interface{}
+	post(t, handler, `{"jsonrpc":"2.0","id":2,"method":"eth_gasPrice"}`, &gasPrice)
+	post(t, handler, `{"jsonrpc":"2.0","id":3,"method":"eth_maxPriorityFeePerGas"}`, &tip)
+	post(t, handler, `{"jsonrpc":"2.0","id":4,"method":"eth_blockNumber"}`, &blockNumber)
+	proxy.response = `[{"jsonrpc":"2.0","id":6,"result":"0x1"}]`
+	var batch []map[string]interface{}
+	post(t, handler, `[{"jsonrpc":"2.0","id":5,"method":"eth_gasPrice"},{"jsonrpc":"2.0","id":6,"method":"eth_chainId"}]`, &batch)
+
+	// assert
+	assert.Equal(t, "0x64", beforeEstimate["result"])
+	assert.Equal(t, "0x2cb417800", gasPrice["result"]) //12 gwei
+	assert.Equal(t, "0x77359400", tip["result"])       //2 gwei above the base fee
+	assert.Equal(t, "0x64", blockNumber["result"])
+	require.Len(t, batch, 2)
+	assert.Equal(t, "0x2cb417800", batch[0]["result"])
+	assert.Equal(t, "0x1", batch[1]["result"])
+	require.Len(t, proxy.forwarded, 3)
+	assert.Equal(t, `[{"jsonrpc":"2.0","id":6,"method":"eth_chainId"}]`, proxy.forwarded[2])
+}
+
+func TestRPCHandlerReplacesFeeHistoryRewards(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	publisher := NewPublisher(snapshot, &headSource{head: &utils.Block{}}, "express", []string{"slow", "fast"}, nil, &recorder{})
+	publisher.AddPrediction(scoring.NewPrediction(100, map[string]int64{"slow": 1, "fast": 3}))
+	proxy := &fakeProxy{response: `{"jsonrpc":"2.0","id":1,"result":{"oldestBlock":"0x63","baseFeePerGas":["0x0","0x0","0x0"],"gasUsedRatio":[0.5,0.5],"reward":[["0x9","0x9"],["0x9","0x9"]]}}`}
+	handler := NewRPCHandler(snapshot, "express", "", proxy, zap.NewNop())
+
+	// act
+	var response struct {
+		Result struct {
+			Reward [][]string `json:"reward"`
+		} `json:"result"`
+	}
+	post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"eth_feeHistory","params":["0x2","latest",[10,90]]}`, &response)
+
+	// assert
+	assert.Equal(t, [][]string{{"0x1", "0x3"}, {"0x1", "0x3"}}, response.Result.Reward)
+}
+
+func TestRPCHandlerRejectsInvalidFeeHistoryPercentiles(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	publisher := NewPublisher(snapshot, &headSource{head: &utils.Block{}}, "express", []string{"slow", "fast"}, nil, &recorder{})
+	publisher.AddPrediction(scoring.NewPrediction(100, map[string]int64{"slow": 1, "fast": 3}))
+	proxy := &fakeProxy{}
+	handler := NewRPCHandler(snapshot, "express", "", proxy, zap.NewNop())
+
+	tests := []struct {
+		name        string
+		percentiles string
+		message     string
+	}{
+		{"above 100", "[150]", "invalid reward percentile: 150"},
+		{"negative", "[-50]", "invalid reward percentile: -50"},
+		{"unsorted", "[50,10]", "invalid reward percentile: #0:50 > #1:10"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			var response rpcResponse
+			post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"eth_feeHistory","params":["0x2","latest",`+test.percentiles+`]}`, &response)
+
+			// assert
+			require.NotNil(t, response.Error)
+			assert.Equal(t, rpcInvalidParams, response.Error.Code)
+			assert.Equal(t, test.message, response.Error.Message)
+			assert.Empty(t, proxy.forwarded)
+		})
+	}
+}
+
+func TestRPCHandlerPassesThroughEmptyFeeHistory(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	publisher := NewPublisher(snapshot, &headSource{head: &utils.Block{}}, "express", []string{"slow", "fast"}, nil, &recorder{})
+	publisher.AddPrediction(scoring.NewPrediction(100, map[string]int64{"slow": 1, "fast": 3}))
+	upstream := `{"jsonrpc":"2.0","id":1,"result":null}`
+	handler := NewRPCHandler(snapshot, "express", "", &fakeProxy{response: upstream}, zap.NewNop())
+	request := `{"jsonrpc":"2.0","id":1,"method":"eth_feeHistory","params":["0x2","latest",[10]]}`
+
+	// act
+	single := httptest.NewRecorder()
+	handler.ServeHTTP(single, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(request)))
+	var batch []json.RawMessage
+	post(t, handler, "["+request+"]", &batch)
+
+	// assert
+	assert.Equal(t, upstream, single.Body.String())
+	require.Len(t, batch, 1)
+	assert.JSONEq(t, upstream, string(batch[0]))
+}
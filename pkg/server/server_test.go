@@ -0,0 +1,174 @@
+package server
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// fixedClock returns a settable time
+type fixedClock struct {
+	now time.Time
+}
+
+func (c *fixedClock) Now() time.Time {
+	return c.now
+}
+
+// headSource serves the same block for every number
+type headSource struct {
+	head  *utils.Block
+	calls int //of GetLastestBlock
+}
+
+func (s *headSource) GetLastestBlock() (*utils.Block, error) {
+	s.calls++
+	return s.head, nil
+}
+
+func (s *headSource) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	return s.head, nil
+}
+
+func (s *headSource) GetBlockByHash(hash common.Hash) (*utils.Block, error) {
+	return s.head, nil
+}
+
+// recorder keeps the predictions without scoring them
+type recorder struct {
+	predictions []*scoring.Prediction
+}
+
+func (r *recorder) AddPrediction(prediction *scoring.Prediction) {
+	r.predictions = append(r.predictions, prediction)
+}
+
+func (r *recorder) PredictScores() error {
+	return nil
+}
+
+func get(t *testing.T, handler http.Handler, path string, body interface{}) int {
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, path, nil))
+	if body != nil {
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), body))
+	}
+
+	return response.Code
+}
+
+func TestHandlerServesPublishedEstimates(t *testing.T) {
+	// arrange
+	clock := &fixedClock{now: time.Unix(1600000000, 0).UTC()}
+	snapshot := NewSnapshot(clock)
+	scores := &recorder{}
+	publisher := NewPublisher(snapshot, &headSource{head: &utils.Block{}}, "naive", []string{"standard"}, map[string]string{"percentile": "60"}, scores)
+	handler := NewHandler(snapshot, zap.NewNop())
+
+	prediction := scoring.NewPrediction(100, map[string]int64{"standard": 1500000000})
+	publisher.AddPrediction(prediction)
+	prediction.Prices["standard"] = 1 //the snapshot holds a copy
+	clock.now = clock.now.Add(3 * time.Second)
+
+	// act
+	var all estimatesResponse
+	allStatus := get(t, handler, "/v1/estimates", &all)
+	var one estimateResponse
+	oneStatus := get(t, handler, "/v1/estimates/naive", &one)
+	unknownStatus := get(t, handler, "/v1/estimates/web3j", nil)
+
+	// assert
+	require.Len(t, scores.predictions, 1)
+	assert.Equal(t, http.StatusOK, allStatus)
+	require.Len(t, all.Estimates, 1)
+	assert.Equal(t, http.StatusOK, oneStatus)
+	assert.Equal(t, "naive", one.Estimator)
+	assert.Equal(t, int64(100), one.BlockNumber)
+	assert.Equal(t, 3.0, one.AgeSeconds)
+	assert.Equal(t, []tierResponse{{Name: "standard", Wei: 1500000000, Gwei: 1.5}}, one.Tiers)
+	assert.Equal(t, map[string]string{"percentile": "60"}, one.Parameters)
+	assert.Equal(t, http.StatusNotFound, unknownStatus)
+}
+
+func TestHandlerRejectsOtherMethods(t *testing.T) {
+	handler := NewHandler(NewSnapshot(nil), zap.NewNop())
+	response := httptest.NewRecorder()
+
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodPost, "/v1/estimates", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, response.Code)
+}
+
+func TestHandlerRendersCompatibilityFormats(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	head := &utils.Block{
+		BaseFee:  (*hexutil.Big)(big.NewInt(10 * utils.GWei)),
+		GasLimit: (*hexutil.Big)(big.NewInt(30000000)),
+		GasUsed:  (*hexutil.Big)(big.NewInt(15000000)),
+	}
+	publisher := NewPublisher(snapshot, &headSource{head: head}, "express", []string{"slow", "standard", "fast", "fastest"}, nil, &recorder{})
+	prediction := scoring.NewPrediction(100, map[string]int64{
+		"slow":     11 * utils.GWei,
+		"standard": 12 * utils.GWei,
+		"fast":     13 * utils.GWei,
+		"fastest":  15 * utils.GWei,
+	})
+	prediction.Claims["standard"] = &scoring.Claim{Probability: 0.6, Blocks: 1}
+	publisher.AddPrediction(prediction)
+	handler := NewHandler(snapshot, zap.NewNop())
+
+	// act
+	var ethGasStation ethGasStationResponse
+	ethGasStationStatus := get(t, handler, "/v1/estimates/express/ethgasstation", &ethGasStation)
+	var etherscan etherscanResponse
+	etherscanStatus := get(t, handler, "/v1/estimates/express?format=etherscan", &etherscan)
+	var eip1559 eip1559Response
+	response := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/v1/estimates/express", nil)
+	request.Header.Set("Accept", "application/json; format=eip1559")
+	handler.ServeHTTP(response, request)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &eip1559))
+	unknownStatus := get(t, handler, "/v1/estimates/express/unknown", nil)
+
+	// assert
+	assert.Equal(t, http.StatusOK, ethGasStationStatus)
+	assert.Equal(t, ethGasStationResponse{
+		SafeLow: 110, Average: 120, Fast: 130, Fastest: 150,
+		AvgWait: 0.2, BlockTime: 12, BlockNum: 100,
+	}, ethGasStation)
+	assert.Equal(t, http.StatusOK, etherscanStatus)
+	assert.Equal(t, etherscanResult{
+		LastBlock: "100", SafeGasPrice: "11", ProposeGasPrice: "12", FastGasPrice: "13",
+		SuggestBaseFee: "10", GasUsedRatio: "0.5",
+	}, etherscan.Result)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, eip1559Level{
+		SuggestedMaxPriorityFeePerGas: "2",
+		SuggestedMaxFeePerGas:         "22",
+		MinWaitTimeEstimate:           12000,
+		MaxWaitTimeEstimate:           12000,
+	}, eip1559.Medium)
+	assert.Equal(t, "10", eip1559.EstimatedBaseFee)
+	assert.Equal(t, http.StatusNotFound, unknownStatus)
+}
+
+func TestLevelsOfFallBackToPrices(t *testing.T) {
+	single := levelsOf(&Estimate{Tiers: []string{"standard"}, Prices: map[string]int64{"standard": 1}})
+	custom := levelsOf(&Estimate{Tiers: []string{"a", "b", "c"}, Prices: map[string]int64{"a": 3, "b": 1, "c": 2}})
+
+	assert.Equal(t, levels{safeLow: "standard", average: "standard", fast: "standard", fastest: "standard"}, single)
+	assert.Equal(t, levels{safeLow: "b", average: "c", fast: "a", fastest: "a"}, custom)
+}
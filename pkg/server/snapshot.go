@@ -0,0 +1,176 @@
+// Package server serves the latest estimates of the running estimators.
+package server
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// Estimate is the latest prediction of an estimator
+type Estimate struct {
+	Estimator    string
+	BlockNumber  int64
+	Tiers        []string                  //in the order of the estimator
+	Prices       map[string]int64          //tier -> gas price in wei
+	Claims       map[string]*scoring.Claim //tier -> claimed probability of inclusion, if any
+	Parameters   map[string]string         //flag name -> value
+	BaseFee      int64                     //base fee of the next block in wei, 0 before london
+	GasUsedRatio float64                   //share of the gas limit used by the block
+	UpdatedAt    time.Time
+}
+
+// Snapshot holds the latest estimate per estimator. It is updated by the
+// estimators and read by the handlers concurrently, estimates are copied in
+// and never modified afterwards. Every update is offered to the subscriptions.
+type Snapshot struct {
+	clock         utils.Clock
+	estimates     map[string]*Estimate
+	subscriptions map[*Subscription]bool
+	mu            sync.RWMutex
+}
+
+// NewSnapshot creates an empty snapshot, clock defaults to the system clock
+func NewSnapshot(clock utils.Clock) *Snapshot {
+	if clock == nil {
+		clock = utils.SystemClock
+	}
+
+	return &Snapshot{
+		clock:         clock,
+		estimates:     make(map[string]*Estimate),
+		subscriptions: make(map[*Subscription]bool),
+	}
+}
+
+// Update replaces the estimate of the estimator, UpdatedAt is set to now
+func (s *Snapshot) Update(estimate Estimate) {
+	prices := make(map[string]int64, len(estimate.Prices))
+	for tier, price := range estimate.Prices {
+		prices[tier] = price
+	}
+	claims := make(map[string]*scoring.Claim, len(estimate.Claims))
+	for tier, claim := range estimate.Claims {
+		copied := *claim
+		claims[tier] = &copied
+	}
+
+	estimate.Tiers = append([]string(nil), estimate.Tiers...)
+	estimate.Prices = prices
+	estimate.Claims = claims
+	estimate.UpdatedAt = s.clock.Now()
+
+	s.mu.Lock()
+	s.estimates[estimate.Estimator] = &estimate
+	for subscription := range s.subscriptions {
+		subscription.offer(&estimate)
+	}
+	s.mu.Unlock()
+}
+
+// Subscribe returns a subscription to the updates matching filter, the
+// current estimates are offered right away
+func (s *Snapshot) Subscribe(filter Filter) *Subscription {
+	subscription := newSubscription(filter)
+
+	s.mu.Lock()
+	for _, estimate := range s.estimates {
+		subscription.offer(estimate)
+	}
+	s.subscriptions[subscription] = true
+	s.mu.Unlock()
+
+	return subscription
+}
+
+// Unsubscribe stops offering updates to the subscription
+func (s *Snapshot) Unsubscribe(subscription *Subscription) {
+	s.mu.Lock()
+	delete(s.subscriptions, subscription)
+	s.mu.Unlock()
+}
+
+// Get returns the estimate of the estimator, false if it has none yet
+func (s *Snapshot) Get(estimator string) (*Estimate, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	estimate, ok := s.estimates[estimator]
+	return estimate, ok
+}
+
+// All returns the estimates ordered by estimator
+func (s *Snapshot) All() []*Estimate {
+	s.mu.RLock()
+	estimates := make([]*Estimate, 0, len(s.estimates))
+	for _, estimate := range s.estimates {
+		estimates = append(estimates, estimate)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i].Estimator < estimates[j].Estimator
+	})
+	return estimates
+}
+
+// Now returns the time of the snapshot clock
+func (s *Snapshot) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Publisher is the scoring.Recorder of a served estimator. It publishes every
+// prediction to the snapshot before passing it on to the scores.
+type Publisher struct {
+	estimator  string
+	tiers      []string
+	parameters map[string]string
+	snapshot   *Snapshot
+	blocks     utils.BlockSource
+	scores     scoring.Recorder
+}
+
+// NewPublisher creates a publisher for the estimator with the given tiers and
+// parameters, the fees of the predicted blocks are loaded from blocks
+func NewPublisher(snapshot *Snapshot, blocks utils.BlockSource, estimator string, tiers []string, parameters map[string]string, scores scoring.Recorder) *Publisher {
+	return &Publisher{
+		estimator:  estimator,
+		tiers:      tiers,
+		parameters: parameters,
+		snapshot:   snapshot,
+		blocks:     blocks,
+		scores:     scores,
+	}
+}
+
+// AddPrediction publishes the prediction and records it
+func (p *Publisher) AddPrediction(prediction *scoring.Prediction) {
+	estimate := Estimate{
+		Estimator:   p.estimator,
+		BlockNumber: prediction.BlockNumber,
+		Tiers:       p.tiers,
+		Prices:      prediction.Prices,
+		Claims:      prediction.Claims,
+		Parameters:  p.parameters,
+	}
+
+	//the block was just loaded by the estimator, without it the fees stay 0
+	block, err := p.blocks.GetBlockByNumber(big.NewInt(prediction.BlockNumber))
+	if err == nil {
+		if baseFee := utils.NextBaseFee(block); baseFee != nil {
+			estimate.BaseFee = baseFee.Int64()
+		}
+		estimate.GasUsedRatio = utils.GasUsedRatio(block)
+	}
+
+	p.snapshot.Update(estimate)
+	p.scores.AddPrediction(prediction)
+}
+
+// PredictScores scores the recorded predictions
+func (p *Publisher) PredictScores() error {
+	return p.scores.PredictScores()
+}
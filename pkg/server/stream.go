@@ -0,0 +1,147 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// Paths the updates are streamed at
+const (
+	StreamPath    = "/v1/stream"    //Server-Sent Events
+	WebSocketPath = "/v1/stream/ws" //WebSocket, one JSON message per estimate
+)
+
+var (
+	//HeartbeatInterval is the interval of the comments keeping idle event streams open
+	HeartbeatInterval = 15 * time.Second
+
+	//WriteTimeout is the time a WebSocket client has to accept a message before it is disconnected
+	WriteTimeout = 10 * time.Second
+)
+
+// stream pushes every update matching the estimator and tier query parameters
+// as a Server-Sent Event named estimate
+func (h *Handler) stream(w http.ResponseWriter, r *http.Request) {
+	if !h.allowGet(w, r) {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "streaming is not supported"})
+		return
+	}
+
+	format := requestedFormat(r)
+	if !contains(Formats, format) {
+		h.writeJSON(w, http.StatusNotAcceptable, errorResponse{Error: fmt.Sprintf("unknown format %v", format)})
+		return
+	}
+
+	subscription := h.snapshot.Subscribe(ParseFilter(r.URL.Query()))
+	defer h.snapshot.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no") //disables buffering of nginx
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		case <-subscription.Ready():
+			for _, estimate := range subscription.Next() {
+				var data []byte
+				data, err = h.message(format, estimate)
+				if err != nil {
+					break
+				}
+				_, err = fmt.Fprintf(w, "event: estimate\nid: %v-%v\ndata: %s\n\n", estimate.Estimator, estimate.BlockNumber, data)
+				if err != nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			h.logger.Debug("closing event stream", zap.Error(err), zap.Int("dropped", subscription.Dropped()))
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// websocketHandler pushes every update matching the estimator and tier query
+// parameters as a JSON message. Clients not accepting a message within
+// WriteTimeout are disconnected.
+func (h *Handler) websocketHandler() http.Handler {
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			return nil //the estimates are public, any origin may subscribe
+		},
+		Handler: h.websocketStream,
+	}
+}
+
+func (h *Handler) websocketStream(conn *websocket.Conn) {
+	defer conn.Close()
+
+	r := conn.Request()
+	format := requestedFormat(r)
+	if !contains(Formats, format) {
+		websocket.JSON.Send(conn, errorResponse{Error: fmt.Sprintf("unknown format %v", format)})
+		return
+	}
+
+	subscription := h.snapshot.Subscribe(ParseFilter(r.URL.Query()))
+	defer h.snapshot.Unsubscribe(subscription)
+
+	//messages of the client are discarded, reading detects the close
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var message []byte
+		for websocket.Message.Receive(conn, &message) == nil {
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-subscription.Ready():
+			for _, estimate := range subscription.Next() {
+				data, err := h.message(format, estimate)
+				if err == nil {
+					conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+					err = websocket.Message.Send(conn, string(data))
+				}
+				if err != nil {
+					h.logger.Debug("closing websocket", zap.Error(err), zap.Int("dropped", subscription.Dropped()))
+					return
+				}
+			}
+		}
+	}
+}
+
+// message renders the estimate of an update in the given format
+func (h *Handler) message(format string, estimate *Estimate) ([]byte, error) {
+	body, err := render(format, estimate, h.snapshot.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(body)
+}
@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+func publish(snapshot *Snapshot, estimator string, block int64, prices map[string]int64) {
+	var tiers []string
+	for _, tier := range []string{"slow", "standard", "fast"} {
+		if _, ok := prices[tier]; ok {
+			tiers = append(tiers, tier)
+		}
+	}
+
+	publisher := NewPublisher(snapshot, &headSource{head: &utils.Block{}}, estimator, tiers, nil, &recorder{})
+	publisher.AddPrediction(scoring.NewPrediction(block, prices))
+}
+
+func TestSubscriptionKeepsLatestEstimatePerEstimator(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	subscription := snapshot.Subscribe(Filter{Estimators: []string{"web3j"}, Tiers: []string{"fast"}})
+
+	// act
+	publish(snapshot, "naive", 100, map[string]int64{"standard": 1})
+	publish(snapshot, "web3j", 100, map[string]int64{"slow": 1, "fast": 2})
+	publish(snapshot, "web3j", 101, map[string]int64{"slow": 3, "fast": 4})
+	snapshot.Unsubscribe(subscription)
+	publish(snapshot, "web3j", 102, map[string]int64{"slow": 5, "fast": 6})
+
+	// assert
+	<-subscription.Ready()
+	estimates := subscription.Next()
+	require.Len(t, estimates, 1)
+	assert.Equal(t, int64(101), estimates[0].BlockNumber)
+	assert.Equal(t, []string{"fast"}, estimates[0].Tiers)
+	assert.Equal(t, map[string]int64{"fast": 4}, estimates[0].Prices)
+	assert.Equal(t, 1, subscription.Dropped())
+	assert.Empty(t, subscription.Next())
+}
+
+func TestStreamPushesServerSentEvents(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	publish(snapshot, "naive", 100, map[string]int64{"standard": 1})
+	server := httptest.NewServer(NewHandler(snapshot, zap.NewNop()))
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/v1/stream?estimator=web3j")
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	// act
+	publish(snapshot, "web3j", 101, map[string]int64{"standard": 2})
+	reader := bufio.NewReader(response.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, strings.TrimSpace(line))
+	}
+
+	// assert
+	assert.Equal(t, "text/event-stream", response.Header.Get("Content-Type"))
+	assert.Equal(t, "event: estimate", lines[0])
+	assert.Equal(t, "id: web3j-101", lines[1])
+	var estimate estimateResponse
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &estimate))
+	assert.Equal(t, "web3j", estimate.Estimator)
+}
+
+func TestStreamPushesWebSocketMessages(t *testing.T) {
+	// arrange
+	snapshot := NewSnapshot(nil)
+	publish(snapshot, "naive", 100, map[string]int64{"standard": 1})
+	server := httptest.NewServer(NewHandler(snapshot, zap.NewNop()))
+	defer server.Close()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/v1/stream/ws?format=etherscan", "", server.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// act
+	var current etherscanResponse
+	require.NoError(t, websocket.JSON.Receive(conn, &current))
+	publish(snapshot, "naive", 101, map[string]int64{"standard": 2 * utils.GWei})
+	var update etherscanResponse
+	require.NoError(t, websocket.JSON.Receive(conn, &update))
+
+	// assert
+	assert.Equal(t, "100", current.Result.LastBlock)
+	assert.Equal(t, "101", update.Result.LastBlock)
+	assert.Equal(t, "2", update.Result.ProposeGasPrice)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+)
+
+// Filter selects the estimators and tiers of a subscription, empty lists
+// select all
+type Filter struct {
+	Estimators []string
+	Tiers      []string
+}
+
+// ParseFilter reads the comma separated estimator and tier query parameters
+func ParseFilter(query url.Values) Filter {
+	split := func(key string) []string {
+		var values []string
+		for _, value := range query[key] {
+			for _, v := range strings.Split(value, ",") {
+				if v = strings.TrimSpace(v); v != "" {
+					values = append(values, v)
+				}
+			}
+		}
+
+		return values
+	}
+
+	return Filter{Estimators: split("estimator"), Tiers: split("tier")}
+}
+
+// apply returns the estimate reduced to the selected tiers, false if the
+// filter selects neither the estimator nor any of its tiers
+func (f Filter) apply(estimate *Estimate) (*Estimate, bool) {
+	if len(f.Estimators) > 0 && !contains(f.Estimators, estimate.Estimator) {
+		return nil, false
+	}
+	if len(f.Tiers) == 0 {
+		return estimate, true
+	}
+
+	filtered := *estimate
+	filtered.Tiers = nil
+	filtered.Prices = make(map[string]int64)
+	filtered.Claims = make(map[string]*scoring.Claim)
+	for _, tier := range estimate.Tiers {
+		if contains(f.Tiers, tier) {
+			filtered.Tiers = append(filtered.Tiers, tier)
+			filtered.Prices[tier] = estimate.Prices[tier]
+			if claim, ok := estimate.Claims[tier]; ok {
+				filtered.Claims[tier] = claim
+			}
+		}
+	}
+	if len(filtered.Tiers) == 0 {
+		return nil, false
+	}
+
+	return &filtered, true
+}
+
+// Subscription receives the updates of a snapshot matching its filter. Only
+// the latest pending estimate per estimator is kept, so a slow subscriber
+// skips superseded estimates instead of queueing them or blocking the
+// estimators.
+type Subscription struct {
+	filter  Filter
+	notify  chan struct{}
+	pending map[string]*Estimate
+	dropped int
+	mu      sync.Mutex
+}
+
+func newSubscription(filter Filter) *Subscription {
+	return &Subscription{
+		filter:  filter,
+		notify:  make(chan struct{}, 1),
+		pending: make(map[string]*Estimate),
+	}
+}
+
+// offer queues the estimate if it matches the filter, never blocks
+func (s *Subscription) offer(estimate *Estimate) {
+	estimate, ok := s.filter.apply(estimate)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	if _, ok := s.pending[estimate.Estimator]; ok {
+		s.dropped++
+	}
+	s.pending[estimate.Estimator] = estimate
+	s.mu.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default: //a notification is already pending
+	}
+}
+
+// Ready is signaled when estimates are pending
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.notify
+}
+
+// Next returns the pending estimates ordered by estimator and clears them
+func (s *Subscription) Next() []*Estimate {
+	s.mu.Lock()
+	estimates := make([]*Estimate, 0, len(s.pending))
+	for _, estimate := range s.pending {
+		estimates = append(estimates, estimate)
+	}
+	s.pending = make(map[string]*Estimate)
+	s.mu.Unlock()
+
+	sort.Slice(estimates, func(i, j int) bool {
+		return estimates[i].Estimator < estimates[j].Estimator
+	})
+	return estimates
+}
+
+// Dropped returns the number of estimates superseded before they were taken
+func (s *Subscription) Dropped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dropped
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
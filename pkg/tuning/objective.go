@@ -0,0 +1,53 @@
+package tuning
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+)
+
+// Objective rates the inclusion summary of a tier
+type Objective struct {
+	Name     string
+	Maximize bool
+	value    func(summary *scoring.InclusionSummary) float64
+}
+
+// Objectives are the supported objectives by name
+var Objectives = map[string]*Objective{
+	"hitRate": {Name: "hitRate", Maximize: true, value: func(s *scoring.InclusionSummary) float64 {
+		return s.HitRate
+	}},
+	"blocks": {Name: "blocks", value: func(s *scoring.InclusionSummary) float64 {
+		return s.MeanBlocks
+	}},
+	"overpayment": {Name: "overpayment", value: func(s *scoring.InclusionSummary) float64 {
+		return s.MeanOverpaymentPercent
+	}},
+	"overspend": {Name: "overspend", value: func(s *scoring.InclusionSummary) float64 {
+		overspend, _ := new(big.Float).SetInt(s.CumulativeOverspendWei).Float64()
+		return overspend
+	}},
+}
+
+// ObjectiveNames lists the supported objectives
+func ObjectiveNames() []string {
+	names := make([]string, 0, len(Objectives))
+	for name := range Objectives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupObjective returns the named objective
+func LookupObjective(name string) (*Objective, error) {
+	objective, ok := Objectives[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown objective %v, supported are %v", name, ObjectiveNames())
+	}
+
+	return objective, nil
+}
@@ -0,0 +1,110 @@
+package tuning
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+)
+
+// DefaultMinHitRate is the hit rate a candidate has to reach by default, so
+// that a few lucky hits of a cheap candidate do not win
+const DefaultMinHitRate = 0.5
+
+// Result is the outcome of backtesting a candidate
+type Result struct {
+	Candidate Candidate
+	Summary   *scoring.InclusionSummary
+	Value     float64
+	Feasible  bool //included at least once and the hit rate reached the required minimum
+}
+
+// NewResult rates the summary of a candidate. Candidates which were never
+// included are infeasible, the means of minimized objectives are taken over
+// the hits only, so their value is +Inf instead of 0.
+func NewResult(candidate Candidate, summary *scoring.InclusionSummary, objective *Objective, minHitRate float64) *Result {
+	hit := summary.Predictions > 0 && summary.HitRate > 0
+	value := objective.value(summary)
+	if !hit && !objective.Maximize {
+		value = math.Inf(1)
+	}
+
+	return &Result{
+		Candidate: candidate,
+		Summary:   summary,
+		Value:     value,
+		Feasible:  hit && summary.HitRate >= minHitRate,
+	}
+}
+
+// Rank sorts the results best first, feasible results rank before the others
+func Rank(results []*Result, objective *Objective) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Feasible != results[j].Feasible {
+			return results[i].Feasible
+		}
+		if objective.Maximize {
+			return results[i].Value > results[j].Value
+		}
+		return results[i].Value < results[j].Value
+	})
+}
+
+// WriteConfig writes the candidate as a json file mapping flag names to
+// values, which is accepted by the --config flag
+func WriteConfig(path string, candidate Candidate) error {
+	data, err := json.MarshalIndent(candidate, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0660)
+}
+
+// WriteRanking writes the ranked results as csv
+func WriteRanking(path string, space Space, results []*Result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	names := space.names()
+	w := csv.NewWriter(f)
+	header := append([]string{"rank"}, names...)
+	header = append(header, "value", "feasible", "predictions", "hitRate", "meanBlocks", "meanOverpaymentPercent")
+	err = w.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for i, result := range results {
+		record := []string{strconv.Itoa(i + 1)}
+		for _, name := range names {
+			record = append(record, strconv.FormatInt(result.Candidate[name], 10))
+		}
+		record = append(record,
+			strconv.FormatFloat(result.Value, 'f', 6, 64),
+			strconv.FormatBool(result.Feasible),
+			strconv.Itoa(result.Summary.Predictions),
+			strconv.FormatFloat(result.Summary.HitRate, 'f', 4, 64),
+			strconv.FormatFloat(result.Summary.MeanBlocks, 'f', 3, 64),
+			strconv.FormatFloat(result.Summary.MeanOverpaymentPercent, 'f', 3, 64),
+		)
+		err = w.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+	if err = w.Error(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,119 @@
+// Package tuning searches the parameters of the estimators by backtesting
+// them and ranking the configurations by an objective.
+package tuning
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+)
+
+// Parameter is the search space of a flag, a list of values or for random
+// search alternatively the integer range [Min, Max]
+type Parameter struct {
+	Values []int64 `json:"values"`
+	Min    int64   `json:"min"`
+	Max    int64   `json:"max"`
+}
+
+// Space maps the flags of an estimator to their search space
+type Space map[string]Parameter
+
+// Candidate is a configuration to backtest, a value per flag
+type Candidate map[string]int64
+
+// LoadSpaces reads a json file mapping estimator names to their search
+// space, e.g. {"naive": {"percentile": {"values": [40, 60, 80]}}}
+func LoadSpaces(path string) (map[string]Space, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var spaces map[string]Space
+	err = json.Unmarshal(data, &spaces)
+	if err != nil {
+		return nil, err
+	}
+	if len(spaces) == 0 {
+		return nil, fmt.Errorf("%v contains no search space", path)
+	}
+
+	return spaces, nil
+}
+
+// Validate checks that every parameter has values, random search also
+// accepts ranges
+func (s Space) Validate(random bool) error {
+	if len(s) == 0 {
+		return fmt.Errorf("search space has no parameters")
+	}
+
+	for name, param := range s {
+		if len(param.Values) > 0 {
+			continue
+		}
+		if !random {
+			return fmt.Errorf("parameter %v has no values, ranges are only supported by random search", name)
+		}
+		if param.Min > param.Max {
+			return fmt.Errorf("parameter %v has an empty range [%v, %v]", name, param.Min, param.Max)
+		}
+	}
+
+	return nil
+}
+
+// names returns the parameter names in a deterministic order
+func (s Space) names() []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Grid returns every combination of the values
+func (s Space) Grid() []Candidate {
+	candidates := []Candidate{{}}
+	for _, name := range s.names() {
+		var next []Candidate
+		for _, candidate := range candidates {
+			for _, value := range s[name].Values {
+				combined := make(Candidate, len(candidate)+1)
+				for k, v := range candidate {
+					combined[k] = v
+				}
+				combined[name] = value
+				next = append(next, combined)
+			}
+		}
+		candidates = next
+	}
+
+	return candidates
+}
+
+// Random samples n candidates, a value is drawn uniformly from the values
+// of a parameter or from its range
+func (s Space) Random(n int, rng *rand.Rand) []Candidate {
+	names := s.names()
+	candidates := make([]Candidate, n)
+	for i := range candidates {
+		candidate := make(Candidate, len(names))
+		for _, name := range names {
+			param := s[name]
+			if len(param.Values) > 0 {
+				candidate[name] = param.Values[rng.Intn(len(param.Values))]
+			} else {
+				candidate[name] = param.Min + rng.Int63n(param.Max-param.Min+1)
+			}
+		}
+		candidates[i] = candidate
+	}
+
+	return candidates
+}
@@ -0,0 +1,82 @@
+package tuning
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGridCombinesAllValues(t *testing.T) {
+	// arrange
+	space := Space{
+		"percentile":     {Values: []int64{40, 60}},
+		"numberOfBlocks": {Values: []int64{10, 20, 40}},
+	}
+
+	// act
+	candidates := space.Grid()
+
+	// assert
+	require.Len(t, candidates, 6)
+	assert.Equal(t, Candidate{"numberOfBlocks": 10, "percentile": 40}, candidates[0])
+	assert.Equal(t, Candidate{"numberOfBlocks": 40, "percentile": 60}, candidates[5])
+}
+
+func TestRandomStaysInRange(t *testing.T) {
+	// arrange
+	space := Space{"probability": {Min: 90, Max: 99}}
+	require.NoError(t, space.Validate(true))
+	require.Error(t, space.Validate(false))
+
+	// act
+	candidates := space.Random(50, rand.New(rand.NewSource(1)))
+
+	// assert
+	for _, candidate := range candidates {
+		assert.True(t, candidate["probability"] >= 90 && candidate["probability"] <= 99)
+	}
+}
+
+func TestRankPrefersFeasibleResults(t *testing.T) {
+	// arrange
+	objective := Objectives["overpayment"]
+	cheap := NewResult(Candidate{"percentile": 20}, &scoring.InclusionSummary{Predictions: 10, HitRate: 0.5, MeanOverpaymentPercent: 1}, objective, 0.9)
+	safe := NewResult(Candidate{"percentile": 60}, &scoring.InclusionSummary{Predictions: 10, HitRate: 0.95, MeanOverpaymentPercent: 10}, objective, 0.9)
+	safer := NewResult(Candidate{"percentile": 80}, &scoring.InclusionSummary{Predictions: 10, HitRate: 0.99, MeanOverpaymentPercent: 20}, objective, 0.9)
+	results := []*Result{cheap, safer, safe}
+
+	// act
+	Rank(results, objective)
+
+	// assert
+	assert.Equal(t, []*Result{safe, safer, cheap}, results)
+}
+
+func TestResultsNeverIncludedAreInfeasible(t *testing.T) {
+	// arrange
+	objective := Objectives["overpayment"]
+	never := &scoring.InclusionSummary{Predictions: 10, CumulativeOverspendWei: big.NewInt(0)}
+	included := &scoring.InclusionSummary{Predictions: 10, HitRate: 0.2, MeanOverpaymentPercent: 30}
+
+	// act
+	tooCheap := NewResult(Candidate{"percentile": 1}, never, objective, 0)
+	lucky := NewResult(Candidate{"percentile": 5}, included, objective, 0)
+	hitRate := NewResult(Candidate{"percentile": 1}, never, Objectives["hitRate"], 0)
+	overspend := NewResult(Candidate{"percentile": 1}, never, Objectives["overspend"], 0)
+	results := []*Result{tooCheap, lucky}
+	Rank(results, objective)
+
+	// assert
+	assert.False(t, tooCheap.Feasible)
+	assert.True(t, math.IsInf(tooCheap.Value, 1))
+	assert.True(t, math.IsInf(overspend.Value, 1))
+	assert.False(t, hitRate.Feasible)
+	assert.Equal(t, 0.0, hitRate.Value)
+	assert.True(t, lucky.Feasible)
+	assert.Equal(t, []*Result{lucky, tooCheap}, results)
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	//ElasticityMultiplier bounds the gas limit of a block to target*multiplier (EIP-1559)
+	ElasticityMultiplier = 2
+
+	//BaseFeeChangeDenominator bounds the change of the base fee per block to 1/denominator (EIP-1559)
+	BaseFeeChangeDenominator = 8
+)
+
+// NextBaseFee returns the base fee of the block following block as defined by
+// EIP-1559, nil for blocks before london
+func NextBaseFee(block *Block) *big.Int {
+	if block.BaseFee == nil || block.GasLimit == nil || block.GasUsed == nil {
+		return nil
+	}
+
+	baseFee := block.BaseFee.ToInt()
+	target := new(big.Int).Div(block.GasLimit.ToInt(), big.NewInt(ElasticityMultiplier))
+	used := block.GasUsed.ToInt()
+	if target.Sign() == 0 || used.Cmp(target) == 0 {
+		return new(big.Int).Set(baseFee)
+	}
+
+	//baseFee * |used-target| / target / denominator
+	delta := new(big.Int).Sub(used, target)
+	delta.Abs(delta)
+	delta.Mul(delta, baseFee)
+	delta.Div(delta, target)
+	delta.Div(delta, big.NewInt(BaseFeeChangeDenominator))
+
+	if used.Cmp(target) > 0 {
+		if delta.Sign() == 0 {
+			delta.SetInt64(1) //the base fee increases by at least 1 wei
+		}
+		return delta.Add(baseFee, delta)
+	}
+
+	next := delta.Sub(baseFee, delta)
+	if next.Sign() < 0 {
+		next.SetInt64(0)
+	}
+	return next
+}
+
+// GasUsedRatio returns the share of the gas limit used by the block
+func GasUsedRatio(block *Block) float64 {
+	if block.GasLimit == nil || block.GasUsed == nil || block.GasLimit.ToInt().Sign() == 0 {
+		return 0
+	}
+
+	used, _ := new(big.Float).SetInt(block.GasUsed.ToInt()).Float64()
+	limit, _ := new(big.Float).SetInt(block.GasLimit.ToInt()).Float64()
+	return used / limit
+}
+
+// EffectiveGasPrice returns the price per gas the transaction paid in the
+// block, i.e. the base fee plus the effective tip since london and the gas
+// price before
+func EffectiveGasPrice(block *Block, tx *types.Transaction) *big.Int {
+	if block.BaseFee == nil {
+		return tx.GasPrice()
+	}
+
+	baseFee := block.BaseFee.ToInt()
+	tip, _ := tx.EffectiveGasTip(baseFee) //included txs pay at least the base fee
+	return new(big.Int).Add(tip, baseFee)
+}
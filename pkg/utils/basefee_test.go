@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func block(baseFee, gasLimit, gasUsed int64) *Block {
+	return &Block{
+		BaseFee:  (*hexutil.Big)(big.NewInt(baseFee)),
+		GasLimit: (*hexutil.Big)(big.NewInt(gasLimit)),
+		GasUsed:  (*hexutil.Big)(big.NewInt(gasUsed)),
+	}
+}
+
+func TestNextBaseFee(t *testing.T) {
+	assert.Equal(t, big.NewInt(1000), NextBaseFee(block(1000, 30000000, 15000000)))
+	assert.Equal(t, big.NewInt(1125), NextBaseFee(block(1000, 30000000, 30000000)))
+	assert.Equal(t, big.NewInt(875), NextBaseFee(block(1000, 30000000, 0)))
+	assert.Equal(t, big.NewInt(8), NextBaseFee(block(7, 30000000, 16000000))) //at least 1 wei increase
+	assert.Nil(t, NextBaseFee(&Block{}))
+	assert.Equal(t, 0.5, GasUsedRatio(block(1000, 30000000, 15000000)))
+}
@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultFailureThreshold is the number of consecutive failures opening the circuit
+	DefaultFailureThreshold = 5
+
+	// DefaultOpenTimeout is the time calls fail fast once the circuit opened
+	DefaultOpenTimeout = 30 * time.Second
+
+	// ErrCircuitOpen is returned instead of calling while the circuit is open
+	ErrCircuitOpen = errors.New("circuit is open")
+)
+
+// Breaker is a circuit breaker. After threshold consecutive failures the
+// circuit opens and calls fail with ErrCircuitOpen for the timeout, then
+// calls are tried again. A successful call closes the circuit.
+type Breaker struct {
+	threshold int
+	timeout   time.Duration
+	clock     Clock
+
+	failures int
+	openedAt time.Time
+	lastErr  error
+	mu       sync.Mutex
+}
+
+// NewBreaker creates a closed breaker, invalid values are replaced by the
+// defaults and clock defaults to the system clock
+func NewBreaker(threshold int, timeout time.Duration, clock Clock) *Breaker {
+	if threshold < 1 {
+		threshold = DefaultFailureThreshold
+	}
+	if timeout <= 0 {
+		timeout = DefaultOpenTimeout
+	}
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	return &Breaker{threshold: threshold, timeout: timeout, clock: clock}
+}
+
+// Call calls fn unless the circuit is open and records its result
+func (b *Breaker) Call(fn func() error) error {
+	if b.Open() {
+		return ErrCircuitOpen
+	}
+
+	err := fn()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.failures = 0
+		return nil
+	}
+
+	b.failures++
+	b.lastErr = err
+	if b.failures >= b.threshold {
+		b.openedAt = b.clock.Now()
+	}
+	return err
+}
+
+// Open reports whether calls currently fail fast
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold && b.clock.Now().Sub(b.openedAt) < b.timeout
+}
+
+// Err returns the last failure, nil if the last call succeeded
+func (b *Breaker) Err() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures == 0 {
+		return nil
+	}
+	return b.lastErr
+}
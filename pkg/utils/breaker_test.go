@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type settableClock struct {
+	now time.Time
+}
+
+func (c *settableClock) Now() time.Time {
+	return c.now
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	// arrange
+	clock := &settableClock{now: time.Unix(1600000000, 0)}
+	breaker := NewBreaker(2, time.Minute, clock)
+	failure := errors.New("connection refused")
+	calls := 0
+	fail := func() error {
+		calls++
+		return failure
+	}
+
+	// act
+	breaker.Call(fail)
+	openAfterOne := breaker.Open()
+	breaker.Call(fail)
+	openErr := breaker.Call(fail)
+	openCalls := calls
+	lastErr := breaker.Err()
+
+	clock.now = clock.now.Add(time.Minute)
+	closedErr := breaker.Call(func() error { return nil })
+
+	// assert
+	assert.False(t, openAfterOne)
+	assert.Equal(t, ErrCircuitOpen, openErr)
+	assert.Equal(t, 2, openCalls)
+	assert.Equal(t, failure, lastErr)
+	assert.NoError(t, closedErr)
+	assert.False(t, breaker.Open())
+	assert.NoError(t, breaker.Err())
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BuilderRule identifies a block builder either by a pattern matched against
+// the extraData of a block or by the fee recipients it uses.
+type BuilderRule struct {
+	Name          string   `json:"name"`
+	ExtraData     string   `json:"extraData"`     //case insensitive regular expression
+	FeeRecipients []string `json:"feeRecipients"` //hex addresses
+}
+
+// DefaultBuilderRules contains builders which tag their blocks in extraData
+var DefaultBuilderRules = []BuilderRule{
+	{Name: "beaverbuild", ExtraData: `beaverbuild`},
+	{Name: "titan", ExtraData: `titan`},
+	{Name: "rsync", ExtraData: `rsync`},
+	{Name: "flashbots", ExtraData: `flashbots`},
+	{Name: "builder0x69", ExtraData: `builder0x69`},
+	{Name: "bloxroute", ExtraData: `bloxroute|blxr`},
+	{Name: "buildai", ExtraData: `buildai`},
+	{Name: "eden", ExtraData: `^eden( network)?$`}, //anchored, "eden" is part of other tags and words
+}
+
+type builderPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// BuilderRegistry maps blocks to the entity that ordered their transactions.
+// Since the merge the miner field of a block is only the fee recipient, which
+// is often a builder or a proposer payment address.
+type BuilderRegistry struct {
+	patterns      []builderPattern
+	feeRecipients map[common.Address]string
+}
+
+// NewBuilderRegistry compiles the given rules
+func NewBuilderRegistry(rules []BuilderRule) (*BuilderRegistry, error) {
+	r := &BuilderRegistry{feeRecipients: make(map[common.Address]string)}
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return nil, errors.New("builder rule without name")
+		}
+
+		for _, feeRecipient := range rule.FeeRecipients {
+			if !common.IsHexAddress(feeRecipient) {
+				return nil, fmt.Errorf("builder %q: invalid fee recipient %q", rule.Name, feeRecipient)
+			}
+			r.feeRecipients[common.HexToAddress(feeRecipient)] = rule.Name
+		}
+
+		if rule.ExtraData != "" {
+			pattern, err := regexp.Compile("(?i)" + rule.ExtraData)
+			if err != nil {
+				return nil, fmt.Errorf("builder %q: %v", rule.Name, err)
+			}
+			r.patterns = append(r.patterns, builderPattern{name: rule.Name, pattern: pattern})
+		}
+	}
+
+	return r, nil
+}
+
+// LoadBuilderRules reads a JSON array of builder rules from the given file
+func LoadBuilderRules(path string) ([]BuilderRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []BuilderRule
+	err = json.Unmarshal(data, &rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// Identify returns the name of the builder of the block. Fee recipients take
+// precedence over extraData patterns. If no rule matches the fee recipient
+// address is returned.
+func (r *BuilderRegistry) Identify(block *Block) string {
+	if r == nil {
+		return block.Miner.String()
+	}
+
+	if name, ok := r.feeRecipients[block.Miner]; ok {
+		return name
+	}
+
+	extraData := strings.ToValidUTF8(string(block.ExtraData), "")
+	for _, p := range r.patterns {
+		if p.pattern.MatchString(extraData) {
+			return p.name
+		}
+	}
+
+	return block.Miner.String()
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestIdentifyBuilder(t *testing.T) {
+	// arrange
+	feeRecipient := common.HexToAddress("0x95222290dd7278aa3ddd389cc1e1d165cc4bafe5")
+	payout := common.HexToAddress("0x1f9090aae28b8a3dceadf281b0f12828e676c326")
+	unknown := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	registry, err := NewBuilderRegistry([]BuilderRule{
+		{Name: "beaverbuild", ExtraData: `beaverbuild`},
+		{Name: "rsync", FeeRecipients: []string{payout.Hex()}},
+	})
+	require.NoError(t, err)
+
+	// act
+	byExtraData := registry.Identify(&Block{Miner: feeRecipient, ExtraData: []byte("BeaverBuild.org")})
+	byFeeRecipient := registry.Identify(&Block{Miner: payout, ExtraData: []byte("beaverbuild.org")})
+	fallback := registry.Identify(&Block{Miner: unknown, ExtraData: []byte("geth")})
+
+	// assert
+	assert.Equal(t, "beaverbuild", byExtraData)
+	assert.Equal(t, "rsync", byFeeRecipient)
+	assert.Equal(t, unknown.String(), fallback)
+}
+
+func TestIdentifyEdenOnlyByItsTag(t *testing.T) {
+	// arrange
+	unknown := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	registry, err := NewBuilderRegistry(DefaultBuilderRules)
+	require.NoError(t, err)
+
+	// act
+	eden := registry.Identify(&Block{Miner: unknown, ExtraData: []byte("Eden Network")})
+	other := registry.Identify(&Block{Miner: unknown, ExtraData: []byte("Sweden staking pool")})
+
+	// assert
+	assert.Equal(t, "eden", eden)
+	assert.Equal(t, unknown.String(), other)
+}
+
+func TestNewBuilderRegistryRejectsInvalidRules(t *testing.T) {
+	_, err := NewBuilderRegistry([]BuilderRule{{Name: "invalid", FeeRecipients: []string{"0x1234"}}})
+	assert.Error(t, err)
+
+	_, err = NewBuilderRegistry([]BuilderRule{{ExtraData: "x"}})
+	assert.Error(t, err)
+}
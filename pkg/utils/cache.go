@@ -16,6 +16,9 @@ import (
 var (
 	DefaultExpiration = 5 * time.Hour
 	ErrBlockNotFound  = errors.New("block was not found")
+
+	//NodeURL is the default JSON-RPC endpoint blocks are loaded from
+	NodeURL = "http://13.80.132.186:8645"
 )
 
 type cacheItem struct {
@@ -25,6 +28,7 @@ type cacheItem struct {
 
 type CachedRPCClient struct {
 	rpcClient  jsonrpc.RPCClient
+	breaker    *Breaker
 	blockCache map[string]*cacheItem
 	janitor    *janitor
 	logger     *zap.Logger
@@ -32,13 +36,18 @@ type CachedRPCClient struct {
 	numberToHash map[int64]string //used to allow both loading by number and hash to be cached
 	//TODO numberToHash should also be cleaned up
 
-	mu sync.RWMutex
+	mu        sync.RWMutex
+	closeOnce sync.Once
 }
 
-func NewCachedRPCClient(logger *zap.Logger) *CachedRPCClient {
-	rpcClient := jsonrpc.NewClient("http://13.80.132.186:8645")
+// NewCachedRPCClient creates a client loading the blocks from the node at url,
+// Close stops its cache janitor. The calls to the node go through a circuit
+// breaker, see Breaker.
+func NewCachedRPCClient(url string, logger *zap.Logger) *CachedRPCClient {
+	rpcClient := jsonrpc.NewClient(url)
 	C := &CachedRPCClient{
 		rpcClient:    rpcClient,
+		breaker:      NewBreaker(DefaultFailureThreshold, DefaultOpenTimeout, nil),
 		blockCache:   make(map[string]*cacheItem),
 		mu:           sync.RWMutex{},
 		logger:       logger,
@@ -85,7 +94,7 @@ func (c *CachedRPCClient) GetBlockByHash(hash common.Hash) (*Block, error) {
 	block, found := c.get(hash.String())
 	if !found {
 		block = new(Block)
-		err := c.rpcClient.CallFor(block, "eth_getBlockByHash", hash, true)
+		err := c.call(block, "eth_getBlockByHash", hash, true)
 		if err != nil {
 			return nil, err
 		}
@@ -97,7 +106,7 @@ func (c *CachedRPCClient) GetBlockByHash(hash common.Hash) (*Block, error) {
 
 func (cache *CachedRPCClient) GetLastestBlock() (*Block, error) {
 	block := new(Block)
-	err := cache.rpcClient.CallFor(block, "eth_getBlockByNumber", "latest", true)
+	err := cache.call(block, "eth_getBlockByNumber", "latest", true)
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +116,7 @@ func (cache *CachedRPCClient) GetLastestBlock() (*Block, error) {
 
 func (cache *CachedRPCClient) GetBlockHeaderByNumber(blockNumber *big.Int) (*BlockHeader, error) {
 	header := new(BlockHeader)
-	err := cache.rpcClient.CallFor(header, "eth_getBlockByNumber", hexutil.Big(*blockNumber), false)
+	err := cache.call(header, "eth_getBlockByNumber", hexutil.Big(*blockNumber), false)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +128,7 @@ func (c *CachedRPCClient) GetBlockByNumber(blockNumber *big.Int) (*Block, error)
 	block, found := c.getByNumber(blockNumber)
 	if !found {
 		block = new(Block)
-		err := c.rpcClient.CallFor(block, "eth_getBlockByNumber", hexutil.Big(*blockNumber), true)
+		err := c.call(block, "eth_getBlockByNumber", hexutil.Big(*blockNumber), true)
 		if err != nil {
 			return nil, err
 		}
@@ -134,6 +143,18 @@ func (c *CachedRPCClient) GetBlockByNumber(blockNumber *big.Int) (*Block, error)
 	return block, nil
 }
 
+// call calls the node through the circuit breaker
+func (c *CachedRPCClient) call(out interface{}, method string, params ...interface{}) error {
+	return c.breaker.Call(func() error {
+		return c.rpcClient.CallFor(out, method, params...)
+	})
+}
+
+// Breaker returns the circuit breaker of the calls to the node
+func (c *CachedRPCClient) Breaker() *Breaker {
+	return c.breaker
+}
+
 // deleteExpired all expired items from the cache.
 func (c *CachedRPCClient) deleteExpired() {
 	c.logger.Info("deleting expired items")
@@ -168,7 +189,15 @@ func (j *janitor) Run(c *CachedRPCClient) {
 }
 
 func stopJanitor(c *CachedRPCClient) {
-	c.janitor.stop <- true
+	c.Close()
+}
+
+// Close stops the janitor expiring the cached blocks
+func (c *CachedRPCClient) Close() {
+	c.closeOnce.Do(func() {
+		runtime.SetFinalizer(c, nil)
+		c.janitor.stop <- true
+	})
 }
 
 func runJanitor(c *CachedRPCClient, ci time.Duration) {
@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// MaxCachedSenders limits the number of senders kept per cache generation
+var MaxCachedSenders = 100000
+
+// TxClassifier decides which transactions of a block reflect the fee market.
+// Senders are recovered with the signer of the configured chain and cached
+// per tx hash, since the same blocks are inspected by every estimator.
+type TxClassifier struct {
+	signer types.Signer
+
+	//two generations are kept, the older one is dropped once the current is full
+	senders     map[common.Hash]common.Address
+	prevSenders map[common.Hash]common.Address
+	mu          sync.RWMutex
+}
+
+// NewTxClassifier creates a classifier for the given chain (1 for mainnet)
+func NewTxClassifier(chainID *big.Int) *TxClassifier {
+	return &TxClassifier{
+		signer:      types.LatestSignerForChainID(chainID),
+		senders:     make(map[common.Hash]common.Address),
+		prevSenders: make(map[common.Hash]common.Address),
+	}
+}
+
+// Sender returns the sender of the transaction
+func (c *TxClassifier) Sender(tx *types.Transaction) (common.Address, error) {
+	hash := tx.Hash()
+	c.mu.RLock()
+	sender, ok := c.senders[hash]
+	if !ok {
+		sender, ok = c.prevSenders[hash]
+	}
+	c.mu.RUnlock()
+	if ok {
+		return sender, nil
+	}
+
+	sender, err := types.Sender(c.signer, tx)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	c.mu.Lock()
+	if len(c.senders) >= MaxCachedSenders {
+		c.prevSenders = c.senders
+		c.senders = make(map[common.Hash]common.Address)
+	}
+	c.senders[hash] = sender
+	c.mu.Unlock()
+	return sender, nil
+}
+
+// IsMarketTx reports whether the price of the transaction was set by the fee
+// market. Zero priced system transactions, transactions sent by the fee
+// recipient (miner or builder self-payments) and payments to the fee
+// recipient (builder pays the proposer) are excluded, as well as
+// transactions whose sender cannot be recovered.
+func (c *TxClassifier) IsMarketTx(block *Block, tx *types.Transaction) bool {
+	if tx.GasPrice().Sign() == 0 {
+		return false
+	}
+
+	if to := tx.To(); to != nil && *to == block.Miner {
+		return false
+	}
+
+	sender, err := c.Sender(tx)
+	return err == nil && sender != block.Miner
+}
+
+// MarketTransactions returns the transactions of the block for which
+// IsMarketTx holds, keeping their order
+func (c *TxClassifier) MarketTransactions(block *Block) Transactions {
+	txs := make(Transactions, 0, len(block.Transactions))
+	for _, tx := range block.Transactions {
+		if c.IsMarketTx(block, tx) {
+			txs = append(txs, tx)
+		}
+	}
+
+	return txs
+}
@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// signer creates transactions signed by a fresh key
+type signer struct {
+	t     *testing.T
+	key   *ecdsa.PrivateKey
+	nonce uint64
+}
+
+func newSigner(t *testing.T) *signer {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	return &signer{t: t, key: key}
+}
+
+func (s *signer) address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// dynamicFee signs a type-2 transaction for the given chain
+func (s *signer) dynamicFee(chainID int64, to common.Address, tip int64) *types.Transaction {
+	s.nonce++
+	tx, err := types.SignNewTx(s.key, types.LatestSignerForChainID(big.NewInt(chainID)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(chainID),
+		Nonce:     s.nonce,
+		GasTipCap: big.NewInt(tip),
+		GasFeeCap: big.NewInt(100 * GWei),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(0),
+	})
+	require.NoError(s.t, err)
+	return tx
+}
+
+// legacy signs a legacy transaction with replay protection for mainnet
+func (s *signer) legacy(to common.Address, gasPrice int64) *types.Transaction {
+	s.nonce++
+	tx, err := types.SignTx(types.NewTransaction(s.nonce, to, big.NewInt(0), 21000, big.NewInt(gasPrice), nil), types.NewEIP155Signer(big.NewInt(1)), s.key)
+	require.NoError(s.t, err)
+	return tx
+}
+
+func TestIsMarketTx(t *testing.T) {
+	// arrange
+	user := newSigner(t)
+	miner := newSigner(t)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	block := &Block{Miner: miner.address()}
+
+	tests := []struct {
+		name   string
+		tx     *types.Transaction
+		market bool
+	}{
+		{"dynamic fee", user.dynamicFee(1, to, GWei), true},
+		{"legacy", user.legacy(to, GWei), true},
+		{"zero price", user.legacy(to, 0), false},
+		{"payment to the miner", user.dynamicFee(1, miner.address(), GWei), false},
+		{"sent by the miner", miner.dynamicFee(1, to, GWei), false},
+		{"signed for another chain", user.dynamicFee(5, to, GWei), false},
+		{"unsigned", types.NewTransaction(0, to, big.NewInt(0), 21000, big.NewInt(GWei), nil), false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			market := NewTxClassifier(big.NewInt(1)).IsMarketTx(block, test.tx)
+
+			// assert
+			assert.Equal(t, test.market, market)
+		})
+	}
+}
+
+func TestSenderRecoversDynamicFeeTxsOfTheChain(t *testing.T) {
+	// arrange
+	user := newSigner(t)
+	tx := user.dynamicFee(1, common.Address{}, GWei)
+	classifier := NewTxClassifier(big.NewInt(1))
+
+	// act
+	sender, err := classifier.Sender(tx)
+	_, otherChainErr := NewTxClassifier(big.NewInt(5)).Sender(tx)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, user.address(), sender)
+	assert.Error(t, otherChainErr)
+}
+
+func TestMarketTransactionsKeepsTheOrder(t *testing.T) {
+	// arrange
+	user := newSigner(t)
+	miner := newSigner(t)
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	first := user.dynamicFee(1, to, 3*GWei)
+	second := user.legacy(to, GWei)
+	block := &Block{Miner: miner.address(), Transactions: Transactions{
+		first,
+		miner.dynamicFee(1, to, GWei),
+		second,
+	}}
+
+	// act
+	txs := NewTxClassifier(big.NewInt(1)).MarketTransactions(block)
+
+	// assert
+	assert.Equal(t, Transactions{first, second}, txs)
+}
+
+func TestSenderCacheRollsOverGenerations(t *testing.T) {
+	// arrange
+	maxCachedSenders := MaxCachedSenders
+	MaxCachedSenders = 2
+	defer func() { MaxCachedSenders = maxCachedSenders }()
+
+	user := newSigner(t)
+	var txs []*types.Transaction
+	for i := int64(1); i <= 5; i++ {
+		txs = append(txs, user.legacy(common.Address{}, i))
+	}
+	classifier := NewTxClassifier(big.NewInt(1))
+	cached := func(tx *types.Transaction) (current bool, previous bool) {
+		_, current = classifier.senders[tx.Hash()]
+		_, previous = classifier.prevSenders[tx.Hash()]
+		return current, previous
+	}
+
+	// act
+	for _, tx := range txs[:3] {
+		_, err := classifier.Sender(tx)
+		require.NoError(t, err)
+	}
+	rolledCurrent, rolledPrevious := cached(txs[0])
+	sender, err := classifier.Sender(txs[0]) //served from the previous generation
+	for _, tx := range txs[3:] {
+		_, err := classifier.Sender(tx)
+		require.NoError(t, err)
+	}
+	droppedCurrent, droppedPrevious := cached(txs[0])
+
+	// assert
+	assert.False(t, rolledCurrent)
+	assert.True(t, rolledPrevious)
+	require.NoError(t, err)
+	assert.Equal(t, user.address(), sender)
+	assert.False(t, droppedCurrent)
+	assert.False(t, droppedPrevious)
+	assert.Len(t, classifier.prevSenders, 2)
+	assert.Len(t, classifier.senders, 1)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BlockSource provides the blocks the estimators work on, e.g. the
+// CachedRPCClient or a simulated chain replaying historical blocks
+type BlockSource interface {
+	GetLastestBlock() (*Block, error)
+	GetBlockByNumber(blockNumber *big.Int) (*Block, error)
+	GetBlockByHash(hash common.Hash) (*Block, error)
+}
+
+// Clock tells the current time, backtests replace the wall clock by the time
+// of the simulated head
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the wall clock
+var SystemClock Clock = systemClock{}
@@ -22,9 +22,11 @@ type Block struct {
 	Hash         common.Hash    `json:"hash"`
 	Miner        common.Address `json:"miner"`
 	Difficulty   *hexutil.Big   `json:"difficulty"`
+	ExtraData    hexutil.Bytes  `json:"extraData"`
 	Number       *hexutil.Big   `json:"number"`
 	GasLimit     *hexutil.Big   `json:"gasLimit"`
 	GasUsed      *hexutil.Big   `json:"gasUsed"`
+	BaseFee      *hexutil.Big   `json:"baseFeePerGas"` //nil before london
 	Time         *hexutil.Big   `json:"timestamp"`
 	Transactions Transactions   `json:"transactions"`
 }
@@ -0,0 +1,111 @@
+package web3j
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// minerAggregate keeps the gas prices per miner of the newest sampleSize
+// blocks of the window. On a new head only the blocks which entered or left
+// the sample are added or evicted instead of aggregating all blocks again.
+type minerAggregate struct {
+	sampleSize int64
+	blocks     map[common.Hash]*windowBlock //blocks in the sample
+	miners     map[string]*minerPrices
+}
+
+type minerPrices struct {
+	blocks    int     //blocks with market transactions
+	gasPrices []int64 //sorted ascending
+}
+
+func newMinerAggregate(sampleSize int64) *minerAggregate {
+	return &minerAggregate{
+		sampleSize: sampleSize,
+		blocks:     make(map[common.Hash]*windowBlock),
+		miners:     make(map[string]*minerPrices),
+	}
+}
+
+// sync moves the sample to the newest sampleSize blocks of the window, blocks
+// dropped by a reorg are evicted since their hashes are no longer part of it
+func (a *minerAggregate) sync(window *blockWindow) {
+	sample := make(map[common.Hash]bool, a.sampleSize)
+	for i := 0; i < len(window.blocks) && int64(i) < a.sampleSize; i++ {
+		sample[window.blocks[i].Hash] = true
+	}
+
+	for hash, block := range a.blocks {
+		if !sample[hash] {
+			a.remove(block)
+		}
+	}
+	for i := 0; i < len(window.blocks) && int64(i) < a.sampleSize; i++ {
+		if _, ok := a.blocks[window.blocks[i].Hash]; !ok {
+			a.add(window.blocks[i])
+		}
+	}
+}
+
+func (a *minerAggregate) add(block *windowBlock) {
+	a.blocks[block.Hash] = block
+	if len(block.Txs) == 0 {
+		return
+	}
+
+	miner := block.Txs[0].Miner //all transactions of a block are ordered by the same miner
+	prices, ok := a.miners[miner]
+	if !ok {
+		prices = &minerPrices{}
+		a.miners[miner] = prices
+	}
+
+	prices.blocks++
+	for _, tx := range block.Txs {
+		gasPrice := tx.GasPrice.Int64()
+		idx := sort.Search(len(prices.gasPrices), func(i int) bool { return prices.gasPrices[i] >= gasPrice })
+		prices.gasPrices = append(prices.gasPrices, 0)
+		copy(prices.gasPrices[idx+1:], prices.gasPrices[idx:])
+		prices.gasPrices[idx] = gasPrice
+	}
+}
+
+func (a *minerAggregate) remove(block *windowBlock) {
+	delete(a.blocks, block.Hash)
+	if len(block.Txs) == 0 {
+		return
+	}
+
+	miner := block.Txs[0].Miner
+	prices := a.miners[miner]
+	prices.blocks--
+	if prices.blocks == 0 {
+		delete(a.miners, miner)
+		return
+	}
+
+	for _, tx := range block.Txs {
+		gasPrice := tx.GasPrice.Int64()
+		idx := sort.Search(len(prices.gasPrices), func(i int) bool { return prices.gasPrices[i] >= gasPrice })
+		prices.gasPrices = append(prices.gasPrices[:idx], prices.gasPrices[idx+1:]...)
+	}
+}
+
+// minerData returns the aggregated data per miner, ordered by miner
+func (a *minerAggregate) minerData() []*minerData {
+	data := make([]*minerData, 0, len(a.miners))
+	for miner, prices := range a.miners {
+		data = append(data, &minerData{
+			Miner:                 miner,
+			Blocks:                prices.blocks,
+			MinGasPrice:           prices.gasPrices[0],
+			LowPercentileGasPrice: prices.gasPrices[(len(prices.gasPrices)-1)*20/100],
+		})
+	}
+	sort.Slice(data, func(i, j int) bool {
+		return data[i].Miner < data[j].Miner
+	})
+
+	return data
+}
@@ -1,7 +1,6 @@
 package web3j
 
 import (
-	"errors"
 	"math"
 	"math/big"
 	"sort"
@@ -9,6 +8,7 @@ import (
 	"time"
 
 	. "github.com/ahmetb/go-linq"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/scoring"
 	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
 	"go.uber.org/zap"
 )
@@ -22,19 +22,32 @@ var (
 type Estimator struct {
 	logger *zap.Logger
 
-	rpcClient    *utils.CachedRPCClient
+	blocks       utils.BlockSource
+	tiers        []Tier
+	window       *blockWindow
+	aggregates   map[int64]*minerAggregate //sampleSize of the tiers -> miner data of the current window
 	mutex        *sync.Mutex
-	scores       *scores
+	scores       scoring.Recorder
 	lastObserved int64
 }
 
-// NewEstimator creates a new estimation.Estimator
-func NewEstimator(logger *zap.Logger, rpcClient *utils.CachedRPCClient) *Estimator {
+// NewEstimator creates a new estimation.Estimator which predicts a gas price
+// for each of the given tiers. Transactions are grouped by the builders
+// identified by the registry, a nil registry groups by fee recipient.
+func NewEstimator(logger *zap.Logger, blocks utils.BlockSource, classifier *utils.TxClassifier, tiers []Tier, builders *utils.BuilderRegistry, scores scoring.Recorder) *Estimator {
+	aggregates := make(map[int64]*minerAggregate)
+	for _, tier := range tiers {
+		aggregates[tier.SampleSize] = newMinerAggregate(tier.SampleSize)
+	}
+
 	return &Estimator{
-		rpcClient: rpcClient,
-		logger:    logger,
-		mutex:     &sync.Mutex{},
-		scores:    newScores(rpcClient, logger),
+		blocks:     blocks,
+		logger:     logger,
+		tiers:      tiers,
+		window:     newBlockWindow(tiers, builders, classifier),
+		aggregates: aggregates,
+		mutex:      &sync.Mutex{},
+		scores:     scores,
 	}
 }
 
@@ -81,11 +94,17 @@ func (e *Estimator) Run() error {
 	return <-errorChannel
 }
 
+// Step estimates the fees once for the latest block of the block source, Run
+// does so on every tick
+func (e *Estimator) Step() error {
+	return e.estimateFees()
+}
+
 func (e *Estimator) estimateFees() error {
 	e.mutex.Lock() //prevents duplicate loading if operation needs longer than tick
 	defer e.mutex.Unlock()
 
-	latest, err := e.rpcClient.GetLastestBlock()
+	latest, err := e.blocks.GetLastestBlock()
 	if err != nil {
 		return err
 	}
@@ -95,68 +114,35 @@ func (e *Estimator) estimateFees() error {
 		return nil
 	}
 
-	//fast: mine within 1 minute
-	fastGasPrice, err := func() (int64, error) {
-		maxWaitSeconds := int64(60)
-		sampleSize := int64(120)
-		probability := int(98)
-		return e.constructTimeBasedStrategy(maxWaitSeconds, sampleSize, probability)()
-	}()
+	err = e.window.update(e.blocks, latest)
 	if err != nil {
-		e.logger.Error("error while predicting fast price", zap.Error(err))
 		return err
 	}
-
-	//medium: mine within 10 minutes
-	mediumGasPrice, err := func() (int64, error) {
-		maxWaitSeconds := int64(600)
-		sampleSize := int64(120)
-		probability := int(98)
-		return e.constructTimeBasedStrategy(maxWaitSeconds, sampleSize, probability)()
-	}()
-	if err != nil {
-		e.logger.Error("error while predicting medium price", zap.Error(err))
-		return err
+	for _, aggregate := range e.aggregates {
+		aggregate.sync(e.window)
 	}
 
-	//slow: mine within 1 hour (60 minutes)
-	slowGasPrice, err := func() (int64, error) {
-		maxWaitSeconds := int64(60 * 60)
-		sampleSize := int64(120)
-		probability := int(98)
-		return e.constructTimeBasedStrategy(maxWaitSeconds, sampleSize, probability)()
-	}()
-	if err != nil {
-		e.logger.Error("error while predicting slow price", zap.Error(err))
-		return err
-	}
+	prices := make(map[string]int64, len(e.tiers))
+	fields := make([]zap.Field, 0, 2*len(e.tiers))
+	for _, tier := range e.tiers {
+		gasPrice, err := e.constructTimeBasedStrategy(tier.MaxWaitSeconds, tier.SampleSize, tier.Probability)()
+		if err != nil {
+			e.logger.Error("error while predicting price", zap.String("tier", tier.Name), zap.Error(err))
+			return err
+		}
 
-	//glacial: mine within the next 24 hours.
-	glacialGasPrice, err := func() (int64, error) {
-		maxWaitSeconds := int64(60 * 60 * 24)
-		sampleSize := int64(720)
-		probability := int(98)
-		return e.constructTimeBasedStrategy(maxWaitSeconds, sampleSize, probability)()
-	}()
-	if err != nil {
-		e.logger.Error("error while predicting glacial price", zap.Error(err))
-		return err
+		prices[tier.Name] = gasPrice
+		fields = append(fields, zap.Int64(tier.Name, gasPrice), zap.Float64(tier.Name+"Gwei", float64(gasPrice)/utils.GWei))
 	}
 
 	e.lastObserved = latestNum
-	e.logger.Info(
-		"predictions",
-		zap.Int64("fast", fastGasPrice),
-		zap.Int64("medium", mediumGasPrice),
-		zap.Int64("slow", slowGasPrice),
-		zap.Int64("glacial", glacialGasPrice),
-		zap.Float64("fastGwei", float64(fastGasPrice)/utils.GWei),
-		zap.Float64("mediumGwei", float64(mediumGasPrice)/utils.GWei),
-		zap.Float64("slowGwei", float64(slowGasPrice)/utils.GWei),
-		zap.Float64("glacialGwei", float64(glacialGasPrice)/utils.GWei),
-	)
-	e.scores.addPrediction(slowGasPrice, mediumGasPrice, fastGasPrice, glacialGasPrice, latestNum)
-	return e.scores.predictScores()
+	e.logger.Info("predictions", fields...)
+	prediction := scoring.NewPrediction(latestNum, prices)
+	for _, tier := range e.tiers {
+		prediction.Claims[tier.Name] = &scoring.Claim{Probability: float64(tier.Probability) / 100, Seconds: tier.MaxWaitSeconds}
+	}
+	e.scores.AddPrediction(prediction)
+	return e.scores.PredictScores()
 }
 
 // A gas pricing strategy that uses recently mined block data to derive a gas
@@ -170,133 +156,53 @@ func (e *Estimator) estimateFees() error {
 //     and 100 means 100%.
 func (e *Estimator) constructTimeBasedStrategy(maxWaitSeconds int64, sampleSize int64, probability int) func() (int64, error) {
 	return func() (int64, error) {
-		avgBlockTime, err := e.getAvgBlockTime(sampleSize)
+		estimate, err := e.estimate(maxWaitSeconds, sampleSize, float64(probability)/100)
 		if err != nil {
 			return 0, err
 		}
-		e.logger.Info("avg block time", zap.Any("time", avgBlockTime))
-
-		maxWaitSecondsFloat := new(big.Float).SetInt64(maxWaitSeconds)
-		waitBlocks, _ := maxWaitSecondsFloat.Quo(maxWaitSecondsFloat, avgBlockTime).Float64()
-		waitBlocks = math.Ceil(waitBlocks)
 
-		rawMinerData, err := e.getRawMinerData(sampleSize)
-		if err != nil {
-			return 0, err
-		}
-		minerData := e.aggregateMinerData(rawMinerData)
-		probabilities := e.computeProbabilities(minerData, waitBlocks, sampleSize)
-		gasPrice := e.computeGasPrice(probabilities, float64(probability)/100)
-		return gasPrice, nil
+		return estimate.GasPrice, nil
 	}
 }
 
-func (e *Estimator) getAvgBlockTime(sampleSize int64) (*big.Float, error) {
-	header, err := e.rpcClient.GetLastestBlock()
+// estimate derives the gas price and the probability curve from the current
+// window. desiredProbability is a floating point representation (e.g. 0.85).
+func (e *Estimator) estimate(maxWaitSeconds int64, sampleSize int64, desiredProbability float64) (*Estimate, error) {
+	avgBlockTime, err := e.window.avgBlockTime(sampleSize)
 	if err != nil {
 		return nil, err
 	}
+	e.logger.Info("avg block time", zap.Any("time", avgBlockTime))
 
-	latestBlockNumber := header.Number.ToInt()
-	constrainedSampleSize := Min(latestBlockNumber, big.NewInt(sampleSize))
-	if constrainedSampleSize.Cmp(big.NewInt(0)) == 0 {
-		return nil, errors.New("Constrained sample size is 0")
-	}
+	maxWaitSecondsFloat := new(big.Float).SetInt64(maxWaitSeconds)
+	waitBlocks, _ := maxWaitSecondsFloat.Quo(maxWaitSecondsFloat, avgBlockTime).Float64()
+	waitBlocks = math.Ceil(waitBlocks)
 
-	oldestBlockNumber := latestBlockNumber.Sub(latestBlockNumber, constrainedSampleSize)
-	oldest, err := e.rpcClient.GetBlockHeaderByNumber(oldestBlockNumber)
-	if err != nil {
-		return nil, err
+	minerData := e.minerData(sampleSize)
+	if len(minerData) == 0 {
+		return nil, ErrNoTransactions
 	}
 
-	latestTime := header.Time.ToInt()
-	diff := latestTime.Sub(latestTime, oldest.Time.ToInt())
-
-	avgBlockTime := new(big.Float).SetInt(diff)
-	constrainedSampleSizeFloat := new(big.Float).SetInt(constrainedSampleSize)
-	return avgBlockTime.Quo(avgBlockTime, constrainedSampleSizeFloat), nil
+	probabilities := e.computeProbabilities(minerData, waitBlocks, sampleSize)
+	return &Estimate{
+		GasPrice:      e.computeGasPrice(probabilities, desiredProbability),
+		BlockNumber:   e.window.blocks[0].Number,
+		WaitBlocks:    waitBlocks,
+		Probabilities: probabilities,
+	}, nil
 }
 
-func (e *Estimator) getRawMinerData(sampleSize int64) ([]*Tx, error) {
-	latest, err := e.rpcClient.GetLastestBlock()
-	if err != nil {
-		return nil, err
-	}
-
-	txs := make([]*Tx, len(latest.Transactions))
-	for i, tx := range latest.Transactions {
-		cleanTx := &Tx{
-			Miner:    latest.Miner.String(),
-			Hash:     latest.Hash.String(),
-			GasPrice: tx.GasPrice(),
-		}
-
-		txs[i] = cleanTx
-	}
-
-	block := latest
-	for i := int64(0); i < sampleSize-1; i++ {
-		blockNumber := block.Number.ToInt()
-		if blockNumber.Cmp(big.NewInt(0)) == 0 {
-			break
-		}
-
-		//we intentionally trace backwards using parent hashes rather than
-		//block numbers to make caching the data easier to implement.
-		loadedBlock, err := e.rpcClient.GetBlockByHash(block.ParentHash)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, tx := range loadedBlock.Transactions {
-			cleanTx := &Tx{
-				Miner:    loadedBlock.Miner.String(),
-				Hash:     loadedBlock.Hash.String(),
-				GasPrice: tx.GasPrice(),
-			}
-
-			txs = append(txs, cleanTx)
-		}
-
-		block = loadedBlock
+// minerData returns the miner data of the newest sampleSize blocks of the
+// window. The aggregates of the tiers are shared by all strategies, other
+// sample sizes are only queried occasionally and aggregated on demand.
+func (e *Estimator) minerData(sampleSize int64) []*minerData {
+	aggregate, ok := e.aggregates[sampleSize]
+	if !ok {
+		aggregate = newMinerAggregate(sampleSize)
+		aggregate.sync(e.window)
 	}
 
-	return txs, nil
-}
-
-func (e *Estimator) aggregateMinerData(txs []*Tx) []*minerData {
-	var query []*minerData
-	From(txs).GroupByT(func(tx *Tx) string {
-		return tx.Miner
-	}, func(tx *Tx) *Tx {
-		return tx
-	}).SelectT(func(group Group) *minerData {
-		miner := group.Key.(string)
-		var gasPrices []int64
-		From(group.Group).SelectT(func(tx *Tx) int64 {
-			return tx.GasPrice.Int64()
-		}).OrderByT(func(gasPrice int64) int64 {
-			return gasPrice
-		}).ToSlice(&gasPrices)
-
-		blocks := From(group.Group).GroupByT(func(tx *Tx) string {
-			return tx.Hash //group by block hash
-		}, func(tx *Tx) *Tx {
-			return tx
-		}).Count()
-
-		pricePercentile := gasPrices[(len(gasPrices)-1)*20/100]
-		//pricePercentile := percentile(gasPrices, 20)
-
-		return &minerData{
-			Miner:                 miner,
-			Blocks:                blocks,
-			MinGasPrice:           MinInt64(gasPrices),
-			LowPercentileGasPrice: pricePercentile,
-		}
-	}).ToSlice(&query)
-
-	return query
+	return aggregate.minerData()
 }
 
 // Computes the probabilities that a txn will be accepted at each of the gas
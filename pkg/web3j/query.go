@@ -0,0 +1,50 @@
+package web3j
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoEstimate is returned if a query is made before the first run
+	ErrNoEstimate = errors.New("no blocks observed yet")
+
+	// ErrNoTransactions is returned if the sampled blocks contain no transactions
+	ErrNoTransactions = errors.New("no transactions in sampled blocks")
+)
+
+// Estimate is the answer to a time based query
+type Estimate struct {
+	GasPrice      int64          `json:"gasPrice"`
+	BlockNumber   int64          `json:"blockNumber"`
+	WaitBlocks    float64        `json:"waitBlocks"`
+	Probabilities []*Probability `json:"probabilities"` //sorted by gas price descending
+}
+
+// Query returns the gas price for which a transaction is mined within
+// maxWaitSeconds with the given probability in percent (0-100, like
+// Tier.Probability), together with the full probability curve it was derived
+// from. It is answered from the blocks
+// cached by the last run, a sampleSize of 0 uses the whole window.
+func (e *Estimator) Query(maxWaitSeconds int64, probability float64, sampleSize int64) (*Estimate, error) {
+	if maxWaitSeconds <= 0 {
+		return nil, fmt.Errorf("maxWaitSeconds must be positive, got %v", maxWaitSeconds)
+	}
+	if probability <= 0 || probability > 100 {
+		return nil, fmt.Errorf("probability must be in (0, 100], got %v", probability)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if len(e.window.blocks) == 0 {
+		return nil, ErrNoEstimate
+	}
+
+	maxSampleSize := int64(e.window.size - 1)
+	if sampleSize <= 0 || sampleSize > maxSampleSize {
+		sampleSize = maxSampleSize
+	}
+
+	return e.estimate(maxWaitSeconds, sampleSize, probability/100)
+}
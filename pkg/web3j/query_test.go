@@ -0,0 +1,117 @@
+package web3j
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// queryEstimator returns an estimator whose window holds the given blocks,
+// newest first, mined every 12 seconds
+func queryEstimator(blocks ...*windowBlock) *Estimator {
+	for i, block := range blocks {
+		block.Number = int64(len(blocks) - 1 - i)
+		block.Hash = common.Hash{byte(block.Number)}
+		block.Time = block.Number * 12
+	}
+
+	return &Estimator{
+		logger:     zap.NewNop(),
+		window:     &blockWindow{size: len(blocks), blocks: blocks},
+		aggregates: make(map[int64]*minerAggregate),
+		mutex:      &sync.Mutex{},
+	}
+}
+
+func minedBy(miner string, gasPrices ...int64) *windowBlock {
+	txs := make([]*Tx, len(gasPrices))
+	for i, gasPrice := range gasPrices {
+		txs[i] = &Tx{Miner: miner, GasPrice: big.NewInt(gasPrice)}
+	}
+	return &windowBlock{Txs: txs}
+}
+
+func TestQuery(t *testing.T) {
+	// arrange
+	//alice accepts 10 in 2 of 3 blocks and bob 20 in 1 block, within 2 blocks
+	//10 is mined with a probability of 1-(1/3)^2 and 20 with 1
+	estimator := queryEstimator(minedBy("alice", 10), minedBy("bob", 20), minedBy("alice", 12), minedBy("carol", 1))
+
+	tests := []struct {
+		name           string
+		maxWaitSeconds int64
+		probability    float64
+		sampleSize     int64
+		gasPrice       int64
+		err            string
+	}{
+		{"certain", 24, 100, 0, 20, ""},
+		{"below the curve", 24, 50, 0, 10, ""},
+		{"interpolated", 24, 95, 0, 16, ""},
+		{"sample of the newest block", 24, 95, 1, 10, ""},
+		{"sample size larger than the window", 24, 100, 10, 20, ""},
+		{"non-positive wait", 0, 95, 0, 0, "maxWaitSeconds must be positive, got 0"},
+		{"zero probability", 24, 0, 0, 0, "probability must be in (0, 100], got 0"},
+		{"fractional scale", 24, 101, 0, 0, "probability must be in (0, 100], got 101"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			estimate, err := estimator.Query(test.maxWaitSeconds, test.probability, test.sampleSize)
+
+			// assert
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.gasPrice, estimate.GasPrice)
+				assert.Equal(t, int64(3), estimate.BlockNumber)
+				assert.Equal(t, 2.0, estimate.WaitBlocks)
+			}
+		})
+	}
+}
+
+func TestQueryCurve(t *testing.T) {
+	// arrange
+	estimator := queryEstimator(minedBy("alice", 10), minedBy("bob", 20), minedBy("alice", 12), minedBy("carol", 1))
+
+	// act
+	estimate, err := estimator.Query(24, 95, 0)
+
+	// assert
+	if assert.NoError(t, err) {
+		assert.Len(t, estimate.Probabilities, 2)
+		assert.Equal(t, &Probability{GasPrice: 20, Probability: 1}, estimate.Probabilities[0])
+		assert.Equal(t, int64(10), estimate.Probabilities[1].GasPrice)
+		assert.InDelta(t, 8.0/9, estimate.Probabilities[1].Probability, 1e-9)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		estimator *Estimator
+		err       error
+	}{
+		{"no blocks", queryEstimator(), ErrNoEstimate},
+		{"no transactions", queryEstimator(minedBy("alice"), minedBy("bob"), minedBy("alice")), ErrNoTransactions},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			_, err := test.estimator.Query(24, 95, 0)
+
+			// assert
+			assert.Equal(t, test.err, err)
+		})
+	}
+}
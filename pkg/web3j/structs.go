@@ -5,18 +5,19 @@ import (
 )
 
 type Tx struct {
-	Miner    string
+	Miner    string //builder or fee recipient which ordered the tx
 	Hash     string
 	GasPrice *big.Int
 }
 
+// Probability is the probability that a tx with the given gas price is accepted
 type Probability struct {
-	GasPrice    int64
-	Probability float64
+	GasPrice    int64   `json:"gasPrice"`
+	Probability float64 `json:"probability"`
 }
 
 type minerData struct {
-	Miner                 string
+	Miner                 string //builder or fee recipient
 	Blocks                int
 	MinGasPrice           int64
 	LowPercentileGasPrice int64
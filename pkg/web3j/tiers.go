@@ -0,0 +1,82 @@
+package web3j
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Tier describes a time based strategy: a gas price for which a transaction
+// is mined within MaxWaitSeconds with the given Probability (0-100), derived
+// from the last SampleSize blocks.
+type Tier struct {
+	Name           string `json:"name"`
+	MaxWaitSeconds int64  `json:"maxWaitSeconds"`
+	SampleSize     int64  `json:"sampleSize"`
+	Probability    int    `json:"probability"`
+}
+
+// DefaultTiers are the strategies used if no tiers are configured
+var DefaultTiers = []Tier{
+	{Name: "slow", MaxWaitSeconds: 60 * 60, SampleSize: 120, Probability: 98},         //mine within 1 hour
+	{Name: "standard", MaxWaitSeconds: 600, SampleSize: 120, Probability: 98},         //mine within 10 minutes
+	{Name: "fast", MaxWaitSeconds: 60, SampleSize: 120, Probability: 98},              //mine within 1 minute
+	{Name: "glacial", MaxWaitSeconds: 60 * 60 * 24, SampleSize: 720, Probability: 98}, //mine within 24 hours
+}
+
+// TierNames returns the names of the tiers
+func TierNames(tiers []Tier) []string {
+	names := make([]string, len(tiers))
+	for i, tier := range tiers {
+		names[i] = tier.Name
+	}
+
+	return names
+}
+
+// LoadTiers reads a JSON array of tiers from the given file
+func LoadTiers(path string) ([]Tier, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var tiers []Tier
+	err = json.Unmarshal(data, &tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	return tiers, ValidateTiers(tiers)
+}
+
+// ValidateTiers checks that tiers are usable by constructTimeBasedStrategy
+func ValidateTiers(tiers []Tier) error {
+	if len(tiers) == 0 {
+		return errors.New("at least one tier is required")
+	}
+
+	names := make(map[string]bool)
+	for _, tier := range tiers {
+		if tier.Name == "" {
+			return errors.New("tier name must not be empty")
+		}
+		if names[tier.Name] {
+			return fmt.Errorf("duplicate tier %q", tier.Name)
+		}
+		names[tier.Name] = true
+
+		if tier.MaxWaitSeconds <= 0 {
+			return fmt.Errorf("tier %q: maxWaitSeconds must be positive", tier.Name)
+		}
+		if tier.SampleSize <= 0 {
+			return fmt.Errorf("tier %q: sampleSize must be positive", tier.Name)
+		}
+		if tier.Probability < 0 || tier.Probability > 100 {
+			return fmt.Errorf("tier %q: probability must be between 0 and 100", tier.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package web3j
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTiers(t *testing.T) {
+	valid := Tier{Name: "fast", MaxWaitSeconds: 60, SampleSize: 120, Probability: 98}
+	with := func(change func(*Tier)) Tier {
+		tier := valid
+		change(&tier)
+		return tier
+	}
+
+	tests := []struct {
+		name  string
+		tiers []Tier
+		err   string
+	}{
+		{"defaults", DefaultTiers, ""},
+		{"probability bounds", []Tier{with(func(t *Tier) { t.Probability = 0 }), with(func(t *Tier) { t.Name, t.Probability = "slow", 100 })}, ""},
+		{"empty", nil, "at least one tier is required"},
+		{"empty name", []Tier{with(func(t *Tier) { t.Name = "" })}, "tier name must not be empty"},
+		{"duplicate name", []Tier{valid, valid}, `duplicate tier "fast"`},
+		{"probability above 100", []Tier{with(func(t *Tier) { t.Probability = 101 })}, `tier "fast": probability must be between 0 and 100`},
+		{"negative probability", []Tier{with(func(t *Tier) { t.Probability = -1 })}, `tier "fast": probability must be between 0 and 100`},
+		{"zero wait", []Tier{with(func(t *Tier) { t.MaxWaitSeconds = 0 })}, `tier "fast": maxWaitSeconds must be positive`},
+		{"negative sample size", []Tier{with(func(t *Tier) { t.SampleSize = -1 })}, `tier "fast": sampleSize must be positive`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// act
+			err := ValidateTiers(test.tiers)
+
+			// assert
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
+
+func TestLoadTiers(t *testing.T) {
+	// arrange
+	dir := t.TempDir()
+	write := func(name string, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+		return path
+	}
+	valid := write("valid.json", `[{"name": "fast", "maxWaitSeconds": 60, "sampleSize": 10, "probability": 90}]`)
+	invalid := write("invalid.json", `[{"name": "fast", "maxWaitSeconds": 60, "sampleSize": 0, "probability": 90}]`)
+	malformed := write("malformed.json", `[{"name": "fast",`)
+
+	// act
+	tiers, err := LoadTiers(valid)
+	_, invalidErr := LoadTiers(invalid)
+	_, malformedErr := LoadTiers(malformed)
+	_, missingErr := LoadTiers(filepath.Join(dir, "missing.json"))
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []Tier{{Name: "fast", MaxWaitSeconds: 60, SampleSize: 10, Probability: 90}}, tiers)
+	assert.EqualError(t, invalidErr, `tier "fast": sampleSize must be positive`)
+	assert.Error(t, malformedErr)
+	assert.True(t, os.IsNotExist(missingErr))
+}
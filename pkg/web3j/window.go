@@ -0,0 +1,127 @@
+package web3j
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// blockWindow keeps the transactions of the most recent blocks (newest first)
+// so that all strategies share one walk over the parent hashes. On every new
+// head only the blocks that are not yet known are loaded.
+type blockWindow struct {
+	size       int
+	blocks     []*windowBlock
+	builders   *utils.BuilderRegistry
+	classifier *utils.TxClassifier
+}
+
+type windowBlock struct {
+	Hash       common.Hash
+	ParentHash common.Hash
+	Number     int64
+	Time       int64
+	Txs        []*Tx
+}
+
+func newBlockWindow(tiers []Tier, builders *utils.BuilderRegistry, classifier *utils.TxClassifier) *blockWindow {
+	maxSampleSize := int64(0)
+	for _, tier := range tiers {
+		if tier.SampleSize > maxSampleSize {
+			maxSampleSize = tier.SampleSize
+		}
+	}
+
+	//one additional block is needed to compute the avg block time
+	return &blockWindow{size: int(maxSampleSize) + 1, builders: builders, classifier: classifier}
+}
+
+// newWindowBlock groups the market transactions of a block by the entity that
+// ordered them, i.e. the builder if it can be identified and the fee
+// recipient otherwise.
+func newWindowBlock(block *utils.Block, builders *utils.BuilderRegistry, classifier *utils.TxClassifier) *windowBlock {
+	miner := builders.Identify(block)
+	marketTxs := classifier.MarketTransactions(block)
+	txs := make([]*Tx, len(marketTxs))
+	for i, tx := range marketTxs {
+		txs[i] = &Tx{
+			Miner:    miner,
+			Hash:     block.Hash.String(),
+			GasPrice: tx.GasPrice(),
+		}
+	}
+
+	return &windowBlock{
+		Hash:       block.Hash,
+		ParentHash: block.ParentHash,
+		Number:     block.Number.ToInt().Int64(),
+		Time:       block.Time.ToInt().Int64(),
+		Txs:        txs,
+	}
+}
+
+// head returns the hash of the newest block in the window
+func (w *blockWindow) head() common.Hash {
+	if len(w.blocks) == 0 {
+		return common.Hash{}
+	}
+
+	return w.blocks[0].Hash
+}
+
+// update moves the window to the given head. It walks backwards using parent
+// hashes until it reaches a block that is already known, so reorgs are
+// handled by dropping the blocks that are no longer canonical.
+func (w *blockWindow) update(blocks utils.BlockSource, latest *utils.Block) error {
+	if len(w.blocks) > 0 && w.head() == latest.Hash {
+		return nil
+	}
+
+	known := make(map[common.Hash]int, len(w.blocks))
+	for idx, block := range w.blocks {
+		known[block.Hash] = idx
+	}
+
+	var fresh []*windowBlock
+	block := latest
+	for len(fresh) < w.size {
+		if idx, ok := known[block.Hash]; ok {
+			fresh = append(fresh, w.blocks[idx:]...)
+			break
+		}
+
+		fresh = append(fresh, newWindowBlock(block, w.builders, w.classifier))
+		if block.Number.ToInt().Sign() == 0 || len(fresh) == w.size {
+			break
+		}
+
+		parent, err := blocks.GetBlockByHash(block.ParentHash)
+		if err != nil {
+			return err
+		}
+		block = parent
+	}
+
+	if len(fresh) > w.size {
+		fresh = fresh[:w.size]
+	}
+	w.blocks = fresh
+	return nil
+}
+
+// avgBlockTime returns the average time between the newest sampleSize blocks
+func (w *blockWindow) avgBlockTime(sampleSize int64) (*big.Float, error) {
+	constrainedSampleSize := sampleSize
+	if available := int64(len(w.blocks) - 1); available < constrainedSampleSize {
+		constrainedSampleSize = available
+	}
+	if constrainedSampleSize <= 0 {
+		return nil, errors.New("Constrained sample size is 0")
+	}
+
+	diff := w.blocks[0].Time - w.blocks[constrainedSampleSize].Time
+	avgBlockTime := new(big.Float).SetInt64(diff)
+	return avgBlockTime.Quo(avgBlockTime, new(big.Float).SetInt64(constrainedSampleSize)), nil
+}
@@ -0,0 +1,168 @@
+package web3j
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/mariusgiger/ethereum-feeestimator/pkg/utils"
+)
+
+// chain serves blocks by hash and counts the loaded blocks
+type chain struct {
+	blocks map[common.Hash]*utils.Block
+	loads  int
+}
+
+func newChain() *chain {
+	return &chain{blocks: make(map[common.Hash]*utils.Block)}
+}
+
+// add adds a block on top of parent, the fork tells apart competing blocks of
+// the same height
+func (c *chain) add(number int64, fork byte, parent *utils.Block) *utils.Block {
+	block := &utils.Block{
+		Hash:   common.Hash{fork, byte(number)},
+		Number: (*hexutil.Big)(big.NewInt(number)),
+		Time:   (*hexutil.Big)(big.NewInt(number * 12)),
+	}
+	if parent != nil {
+		block.ParentHash = parent.Hash
+	}
+	c.blocks[block.Hash] = block
+	return block
+}
+
+func (c *chain) GetLastestBlock() (*utils.Block, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *chain) GetBlockByNumber(blockNumber *big.Int) (*utils.Block, error) {
+	return nil, errors.New("not supported")
+}
+
+func (c *chain) GetBlockByHash(hash common.Hash) (*utils.Block, error) {
+	c.loads++
+	block, ok := c.blocks[hash]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return block, nil
+}
+
+func numbers(w *blockWindow) []int64 {
+	var numbers []int64
+	for _, block := range w.blocks {
+		numbers = append(numbers, block.Number)
+	}
+	return numbers
+}
+
+func TestBlockWindowFillsGaps(t *testing.T) {
+	// arrange
+	chain := newChain()
+	var blocks []*utils.Block
+	var parent *utils.Block
+	for number := int64(0); number <= 10; number++ {
+		parent = chain.add(number, 0, parent)
+		blocks = append(blocks, parent)
+	}
+	window := &blockWindow{size: 4}
+
+	// act
+	err := window.update(chain, blocks[5])
+	initial := numbers(window)
+	chain.loads = 0
+	gapErr := window.update(chain, blocks[7]) //skips block 6
+	gapLoads := chain.loads
+	gap := numbers(window)
+	chain.loads = 0
+	sameErr := window.update(chain, blocks[7])
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []int64{5, 4, 3, 2}, initial)
+	require.NoError(t, gapErr)
+	assert.Equal(t, []int64{7, 6, 5, 4}, gap)
+	assert.Equal(t, 2, gapLoads) //block 6 and the known block 5
+	require.NoError(t, sameErr)
+	assert.Equal(t, 0, chain.loads)
+}
+
+func TestBlockWindowHandlesReorgs(t *testing.T) {
+	// arrange
+	chain := newChain()
+	var canonical []*utils.Block
+	var parent *utils.Block
+	for number := int64(0); number <= 5; number++ {
+		parent = chain.add(number, 0, parent)
+		canonical = append(canonical, parent)
+	}
+	fork4 := chain.add(4, 1, canonical[3])
+	fork5 := chain.add(5, 1, fork4)
+	fork6 := chain.add(6, 1, fork5)
+	orphan := chain.add(7, 2, &utils.Block{Hash: common.Hash{0xff}})
+	window := &blockWindow{size: 4}
+	require.NoError(t, window.update(chain, canonical[5]))
+
+	// act
+	err := window.update(chain, fork6)
+	reorged := window.blocks
+	orphanErr := window.update(chain, orphan)
+
+	// assert
+	require.NoError(t, err)
+	require.Len(t, reorged, 4)
+	assert.Equal(t, []common.Hash{fork6.Hash, fork5.Hash, fork4.Hash, canonical[3].Hash},
+		[]common.Hash{reorged[0].Hash, reorged[1].Hash, reorged[2].Hash, reorged[3].Hash})
+	assert.EqualError(t, orphanErr, "not found")
+	assert.Equal(t, fork6.Hash, window.head()) //the window is kept if the parent is unknown
+}
+
+func TestMinerAggregateEvictsBlocksLeavingTheSample(t *testing.T) {
+	// arrange
+	block := func(number int64, fork byte, miner string, gasPrices ...int64) *windowBlock {
+		hash := common.Hash{fork, byte(number)}
+		txs := make([]*Tx, len(gasPrices))
+		for i, gasPrice := range gasPrices {
+			txs[i] = &Tx{Miner: miner, Hash: hash.String(), GasPrice: big.NewInt(gasPrice)}
+		}
+		return &windowBlock{Hash: hash, Number: number, Txs: txs}
+	}
+	b1 := block(1, 0, "alice", 5, 1, 3)
+	b2 := block(2, 0, "bob", 8)
+	b3 := block(3, 0, "alice", 2)
+	b3Fork := block(3, 1, "carol", 4, 6)
+	window := &blockWindow{blocks: []*windowBlock{b2, b1}}
+	aggregate := newMinerAggregate(2)
+
+	// act
+	aggregate.sync(window)
+	initial := aggregate.minerData()
+	window.blocks = []*windowBlock{b3, b2, b1}
+	aggregate.sync(window)
+	moved := aggregate.minerData()
+	window.blocks = []*windowBlock{b3Fork, b2, b1}
+	aggregate.sync(window)
+	reorged := aggregate.minerData()
+
+	// assert
+	assert.Equal(t, []*minerData{
+		{Miner: "alice", Blocks: 1, MinGasPrice: 1, LowPercentileGasPrice: 1},
+		{Miner: "bob", Blocks: 1, MinGasPrice: 8, LowPercentileGasPrice: 8},
+	}, initial)
+	assert.Equal(t, []*minerData{
+		{Miner: "alice", Blocks: 1, MinGasPrice: 2, LowPercentileGasPrice: 2},
+		{Miner: "bob", Blocks: 1, MinGasPrice: 8, LowPercentileGasPrice: 8},
+	}, moved)
+	assert.Equal(t, []*minerData{
+		{Miner: "bob", Blocks: 1, MinGasPrice: 8, LowPercentileGasPrice: 8},
+		{Miner: "carol", Blocks: 1, MinGasPrice: 4, LowPercentileGasPrice: 4},
+	}, reorged)
+	assert.Len(t, aggregate.blocks, 2)
+}